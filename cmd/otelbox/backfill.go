@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/neox5/otelbox/internal/app"
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/otelbox/internal/version"
+	"github.com/urfave/cli/v3"
+)
+
+func backfillCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "backfill",
+		Usage: "write historical samples as OpenMetrics text for promtool tsdb create-blocks-from openmetrics",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "from",
+				Usage:    "start of the backfill, RFC 3339 timestamp or offset from now (e.g. -168h)",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "to",
+				Value: "0s",
+				Usage: "end of the backfill, RFC 3339 timestamp or offset from now",
+			},
+			&cli.StringFlag{
+				Name:     "out",
+				Aliases:  []string{"o"},
+				Usage:    "path of the OpenMetrics output file",
+				Required: true,
+			},
+			&cli.DurationFlag{
+				Name:  "interval",
+				Value: 15 * time.Second,
+				Usage: "time between samples, like a scrape interval",
+			},
+		},
+		Action: backfill,
+	}
+}
+
+// backfill runs the simulation from --from to --to at maximum speed and
+// writes the samples to --out. No exporter is started.
+func backfill(ctx context.Context, cmd *cli.Command) error {
+	configPath := cmd.String("config")
+	setupLogging(cmd.Bool("debug"))
+
+	slog.Info("starting otelbox backfill", "version", version.String(), "config", configPath)
+
+	now := time.Now()
+	from, err := config.ParseTimePoint(cmd.String("from"), now)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to, err := config.ParseTimePoint(cmd.String("to"), now)
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	if !to.After(from) {
+		return fmt.Errorf("--to must be after --from")
+	}
+	interval := cmd.Duration("interval")
+	if interval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+
+	cfg, err := loadConfig(configPath)
+	if err != nil {
+		return err
+	}
+
+	// Replaces time settings of the configuration
+	cfg.Settings.Time = config.TimeConfig{Start: from, End: to}
+
+	bf, err := app.NewBackfill(cfg, cmd.String("out"), interval)
+	if err != nil {
+		return fmt.Errorf("initialization failed: %w", err)
+	}
+
+	// Setup graceful shutdown
+	shutdownCtx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	bf.Generator.Start()
+
+	select {
+	case <-bf.Generator.Done():
+	case <-shutdownCtx.Done():
+		bf.Generator.Stop()
+		bf.Writer.Abort()
+		return fmt.Errorf("backfill interrupted")
+	}
+
+	bf.Generator.Stop()
+	return bf.Writer.Close()
+}
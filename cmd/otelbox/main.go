@@ -9,6 +9,7 @@ import (
 	"sync"
 	"syscall"
 	"time"
+	_ "time/tzdata" // Time zones of cron clocks and load profiles, the container image has no zoneinfo
 
 	"github.com/neox5/otelbox/internal/app"
 	"github.com/neox5/otelbox/internal/config"
@@ -35,6 +36,9 @@ func main() {
 			},
 		},
 		Action: serve,
+		Commands: []*cli.Command{
+			backfillCommand(),
+		},
 	}
 
 	if err := cmd.Run(context.Background(), os.Args); err != nil {
@@ -45,55 +49,15 @@ func main() {
 
 func serve(ctx context.Context, cmd *cli.Command) error {
 	configPath := cmd.String("config")
-	debug := cmd.Bool("debug")
-
-	// Configure logging level
-	logLevel := slog.LevelInfo
-	if debug {
-		logLevel = slog.LevelDebug
-	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
-		Level: logLevel,
-	}))
-	slog.SetDefault(logger)
+	logger := setupLogging(cmd.Bool("debug"))
 
 	slog.Info("starting otelbox", "version", version.String(), "config", configPath)
 
-	// Load configuration
-	raw, err := config.Parse(configPath)
+	cfg, err := loadConfig(configPath)
 	if err != nil {
-		return fmt.Errorf("failed to parse config: %w", err)
+		return err
 	}
 
-	// Log pre-expansion counts
-	slog.Info("configuration parsed",
-		"iterators", len(raw.Iterators),
-		"templates.clocks", len(raw.Templates.Clocks),
-		"templates.sources", len(raw.Templates.Sources),
-		"templates.values", len(raw.Templates.Values),
-		"instances.clocks", len(raw.Instances.Clocks),
-		"instances.sources", len(raw.Instances.Sources),
-		"instances.values", len(raw.Instances.Values),
-		"metrics", len(raw.Metrics))
-
-	// Expand configuration
-	if err = config.Expand(raw); err != nil {
-		return fmt.Errorf("failed to expand config: %w", err)
-	}
-
-	// Resolve configuration
-	cfg, err := config.Resolve(raw)
-	if err != nil {
-		return fmt.Errorf("failed to resolve config: %w", err)
-	}
-
-	// Log post-expansion counts
-	slog.Info("configuration expanded",
-		"clocks", len(cfg.Instances.Clocks),
-		"sources", len(cfg.Instances.Sources),
-		"values", len(cfg.Instances.Values),
-		"metrics", len(cfg.Metrics))
-
 	// Initialize application (handles seed initialization internally)
 	application, err := app.New(cfg)
 	if err != nil {
@@ -138,6 +102,9 @@ func serve(ctx context.Context, cmd *cli.Command) error {
 	case err := <-errChan:
 		slog.Error("exporter error", "error", err)
 		stop() // Cancel context to trigger shutdown
+	case <-application.Generator.Done():
+		// Virtual time reached its end
+		stop()
 	case <-shutdownCtx.Done():
 		// Graceful shutdown triggered
 	}
@@ -151,3 +118,56 @@ func serve(ctx context.Context, cmd *cli.Command) error {
 	slog.Info("shutdown complete")
 	return nil
 }
+
+// setupLogging configures the default logger.
+func setupLogging(debug bool) *slog.Logger {
+	logLevel := slog.LevelInfo
+	if debug {
+		logLevel = slog.LevelDebug
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: logLevel,
+	}))
+	slog.SetDefault(logger)
+	return logger
+}
+
+// loadConfig parses, expands and resolves the configuration file.
+func loadConfig(configPath string) (*config.Config, error) {
+	raw, err := config.Parse(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	// Log pre-expansion counts
+	slog.Info("configuration parsed",
+		"iterators", len(raw.Iterators),
+		"templates.clocks", len(raw.Templates.Clocks),
+		"templates.sources", len(raw.Templates.Sources),
+		"templates.values", len(raw.Templates.Values),
+		"templates.metrics", len(raw.Templates.Metrics),
+		"instances.clocks", len(raw.Instances.Clocks),
+		"instances.sources", len(raw.Instances.Sources),
+		"instances.values", len(raw.Instances.Values),
+		"metrics", len(raw.Metrics))
+
+	// Expand configuration
+	if err = config.Expand(raw); err != nil {
+		return nil, fmt.Errorf("failed to expand config: %w", err)
+	}
+
+	// Resolve configuration
+	cfg, err := config.Resolve(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config: %w", err)
+	}
+
+	// Log post-expansion counts
+	slog.Info("configuration expanded",
+		"clocks", len(cfg.Instances.Clocks),
+		"sources", len(cfg.Instances.Sources),
+		"values", len(cfg.Instances.Values),
+		"metrics", len(cfg.Metrics))
+
+	return cfg, nil
+}
@@ -22,19 +22,9 @@ type App struct {
 // New initializes the application from configuration.
 // Seed must be initialized before calling this function.
 func New(cfg *config.Config) (*App, error) {
-	// Initialize seed before creating any simv objects
-	simulation.InitializeSeed(&cfg.Settings)
-
-	// Create generator from metrics
-	gen, err := generator.New(cfg.Metrics)
+	gen, metrics, err := newSimulation(cfg)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create generator: %w", err)
-	}
-
-	// Create metrics
-	metrics, err := metric.New(cfg, gen)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create metrics: %w", err)
+		return nil, err
 	}
 
 	var promExporter *exporter.PrometheusExporter
@@ -69,3 +59,26 @@ func New(cfg *config.Config) (*App, error) {
 		OTELExporter:       otelExporter,
 	}, nil
 }
+
+// newSimulation creates generator and metrics from configuration.
+func newSimulation(cfg *config.Config) (*generator.Generator, *metric.Registry, error) {
+	// Initialize seed before creating any simv objects
+	simulation.InitializeSeed(&cfg.Settings)
+
+	// Initialize time before creating any clocks
+	simulation.InitializeTime(&cfg.Settings)
+
+	// Create generator from metrics
+	gen, err := generator.New(cfg.Metrics)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create generator: %w", err)
+	}
+
+	// Create metrics
+	metrics, err := metric.New(cfg, gen)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create metrics: %w", err)
+	}
+
+	return gen, metrics, nil
+}
@@ -0,0 +1,45 @@
+package app
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/otelbox/internal/exporter"
+	"github.com/neox5/otelbox/internal/generator"
+	"github.com/neox5/otelbox/internal/metric"
+)
+
+// Backfill holds components generating historical data into a file.
+type Backfill struct {
+	Config    *config.Config
+	Generator *generator.Generator
+	Metrics   *metric.Registry
+	Writer    *exporter.OpenMetricsWriter
+}
+
+// NewBackfill initializes a backfill writing samples every interval to path.
+// Configured exporters are not created. Time settings must run at maximum
+// speed with an end.
+func NewBackfill(cfg *config.Config, path string, interval time.Duration) (*Backfill, error) {
+	if !cfg.Settings.Time.MaxSpeed() || cfg.Settings.Time.End.IsZero() {
+		return nil, fmt.Errorf("backfill requires virtual time at maximum speed with end")
+	}
+
+	gen, metrics, err := newSimulation(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	writer, err := exporter.NewOpenMetricsWriter(path, interval, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenMetrics writer: %w", err)
+	}
+
+	return &Backfill{
+		Config:    cfg,
+		Generator: gen,
+		Metrics:   metrics,
+		Writer:    writer,
+	}, nil
+}
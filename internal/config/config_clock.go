@@ -3,19 +3,74 @@ package config
 import (
 	"log/slog"
 	"time"
+
+	"github.com/neox5/otelbox/internal/cron"
 )
 
 // ClockConfig defines a fully resolved clock
 type ClockConfig struct {
 	Type     string
-	Interval time.Duration
+	Interval time.Duration // periodic and jitter
+	Jitter   time.Duration // jitter only, maximum deviation from interval
+	Rate     float64       // poisson only, mean ticks per second
+	Schedule string        // cron only
+	Timezone string        // cron only, local time if empty
+}
+
+// MeanInterval returns the average time between ticks.
+func (c ClockConfig) MeanInterval() time.Duration {
+	switch c.Type {
+	case "poisson":
+		if c.Rate > 0 {
+			return time.Duration(float64(time.Second) / c.Rate)
+		}
+	case "cron":
+		if schedule, err := cron.Parse(c.Schedule); err == nil {
+			return schedule.MeanInterval()
+		}
+	}
+	return c.Interval
+}
+
+// Location returns the time zone of the cron schedule.
+// Falls back to local time if the timezone is unset or invalid.
+func (c ClockConfig) Location() *time.Location {
+	loc, err := loadLocation(c.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// loadLocation loads a time zone by IANA name, local time if empty.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return time.Local, nil
+	}
+	return time.LoadLocation(name)
 }
 
 // LogValue implements slog.LogValuer for structured logging
 func (c ClockConfig) LogValue() slog.Value {
 	attrs := []slog.Attr{
 		slog.String("type", c.Type),
-		slog.Duration("interval", c.Interval),
 	}
+
+	// Only log parameters relevant to the clock type
+	switch c.Type {
+	case "jitter":
+		attrs = append(attrs,
+			slog.Duration("interval", c.Interval),
+			slog.Duration("jitter", c.Jitter))
+	case "poisson":
+		attrs = append(attrs, slog.Float64("rate", c.Rate))
+	case "cron":
+		attrs = append(attrs,
+			slog.String("schedule", c.Schedule),
+			slog.String("timezone", c.Location().String()))
+	default:
+		attrs = append(attrs, slog.Duration("interval", c.Interval))
+	}
+
 	return slog.GroupValue(attrs...)
 }
@@ -60,11 +60,6 @@ func (e *ExportConfig) Validate() error {
 		return fmt.Errorf("at least one exporter must be enabled")
 	}
 
-	// Verify only one exporter enabled (prevent read conflicts)
-	if promEnabled && otelEnabled {
-		return fmt.Errorf("only one exporter can be enabled at a time (prometheus or otel)")
-	}
-
 	return nil
 }
 
@@ -106,6 +101,20 @@ type OTELExportConfig struct {
 	Interval  IntervalConfig
 	Resource  map[string]string
 	Headers   map[string]string
+	File      *OTELFileConfig // file transport only
+}
+
+// OTELFileConfig defines the output of the file transport.
+type OTELFileConfig struct {
+	Path     string
+	Rotation RotationConfig
+}
+
+// RotationConfig defines when output files are rotated, zero values disable rotation.
+type RotationConfig struct {
+	MaxMegabytes int           // Rotate before the file exceeds this size
+	Interval     time.Duration // Rotate once the file is older
+	MaxBackups   int           // Rotated files to keep, 0 keeps all
 }
 
 // IntervalConfig defines read and push intervals for OTEL.
@@ -126,8 +135,17 @@ func (c *OTELExportConfig) Validate() error {
 	}
 
 	// Validate transport
-	if c.Transport != "grpc" && c.Transport != "http" {
-		return fmt.Errorf("invalid transport: %s (must be grpc or http)", c.Transport)
+	switch c.Transport {
+	case "grpc", "http":
+		if c.File != nil {
+			return fmt.Errorf("file settings require transport file")
+		}
+	case "file":
+		if err := c.File.validate(); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("invalid transport: %s (must be grpc, http or file)", c.Transport)
 	}
 
 	// Apply host default
@@ -166,7 +184,27 @@ func (c *OTELExportConfig) Validate() error {
 	return nil
 }
 
-// GetEndpoint returns the full endpoint address.
+// GetEndpoint returns the full endpoint address, the output path for the file transport.
 func (c *OTELExportConfig) GetEndpoint() string {
+	if c.Transport == "file" {
+		return c.File.Path
+	}
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
+
+// validate validates the file transport configuration.
+func (f *OTELFileConfig) validate() error {
+	if f == nil || f.Path == "" {
+		return fmt.Errorf("file transport requires file.path")
+	}
+	if f.Rotation.MaxMegabytes < 0 {
+		return fmt.Errorf("invalid rotation max_megabytes: %d (must be >= 0)", f.Rotation.MaxMegabytes)
+	}
+	if f.Rotation.Interval < 0 {
+		return fmt.Errorf("invalid rotation interval: %s (must be >= 0)", f.Rotation.Interval)
+	}
+	if f.Rotation.MaxBackups < 0 {
+		return fmt.Errorf("invalid rotation max_backups: %d (must be >= 0)", f.Rotation.MaxBackups)
+	}
+	return nil
+}
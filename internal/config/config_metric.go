@@ -15,17 +15,89 @@ type MetricConfig struct {
 	PrometheusName string
 	OTELName       string
 	Type           MetricType
+	ValueType      ValueType
 	Description    string
 	Value          ValueConfig
+	Read           ReadMode        // How exporters read the value
+	Histogram      HistogramConfig // Only used by histogram metrics
+	Summary        SummaryConfig   // Only used by summary metrics
+	StateSet       StateSetConfig  // Only used by stateset metrics
 	Attributes     map[string]string
 }
 
+// HistogramConfig defines histogram aggregation
+type HistogramConfig struct {
+	Mode       HistogramMode
+	Buckets    []float64 // Explicit mode: upper bounds, strictly increasing (+Inf implicit)
+	Scale      int       // Exponential mode: initial (maximum) scale
+	MaxBuckets int       // Exponential mode: maximum buckets per sign before downscaling
+}
+
+// SummaryConfig defines summary aggregation
+type SummaryConfig struct {
+	Quantiles []float64 // Strictly increasing, each within [0, 1]
+	Window    int       // Number of most recent observations used for quantiles
+}
+
+// Summary defaults
+var DefaultSummaryQuantiles = []float64{0.5, 0.9, 0.99}
+
+const DefaultSummaryWindow = 1000
+
+// StateSetConfig defines the states of a stateset metric
+type StateSetConfig struct {
+	States      []string
+	Label       string                        // Attribute holding the state name
+	Initial     string                        // Active state before the first update
+	Transitions map[string]map[string]float64 // Weighted next states per state, nil selects state by value
+}
+
+// DefaultStateSetLabel is the attribute holding the state name
+const DefaultStateSetLabel = "state"
+
+// HistogramMode defines the bucket layout of a histogram
+type HistogramMode string
+
+const (
+	HistogramModeExplicit    HistogramMode = "explicit"
+	HistogramModeExponential HistogramMode = "exponential"
+)
+
+// Exponential histogram defaults and limits
+const (
+	DefaultHistogramScale      = 20
+	DefaultHistogramMaxBuckets = 160
+	MinHistogramScale          = -4 // Lowest Prometheus native histogram schema
+	MaxHistogramScale          = 20 // Highest OTEL exponential histogram scale
+)
+
 // MetricType defines the semantic type of a metric
 type MetricType string
 
 const (
-	MetricTypeCounter MetricType = "counter"
-	MetricTypeGauge   MetricType = "gauge"
+	MetricTypeCounter       MetricType = "counter"
+	MetricTypeGauge         MetricType = "gauge"
+	MetricTypeUpDownCounter MetricType = "updowncounter"
+	MetricTypeHistogram     MetricType = "histogram"
+	MetricTypeSummary       MetricType = "summary"
+	MetricTypeInfo          MetricType = "info"
+	MetricTypeStateSet      MetricType = "stateset"
+)
+
+// ReadMode defines how exporters read a metric's value
+type ReadMode string
+
+const (
+	ReadModeConsume ReadMode = "consume" // Read triggers reset on read
+	ReadModePeek    ReadMode = "peek"    // Read leaves the value unchanged
+)
+
+// ValueType defines the numeric representation of exported metric values
+type ValueType string
+
+const (
+	ValueTypeInt   ValueType = "int"
+	ValueTypeFloat ValueType = "float"
 )
 
 // IsValidAttributeName checks if an attribute name follows conventions
@@ -43,17 +115,63 @@ func IsValidAttributeName(name string) bool {
 func (m MetricConfig) LogValue() slog.Value {
 	// Determine value name
 	valueName := "inline"
-	if m.Value.SourceRef != nil {
-		valueName = "instance:" + *m.Value.SourceRef
+	if m.Value.ValueRef != nil {
+		valueName = "instance:" + *m.Value.ValueRef
+	}
+	if m.Value.Derive != nil {
+		valueName = "derive:" + m.Value.Derive.String()
+	}
+	if m.Value.Expr != nil {
+		valueName = "expr:" + m.Value.Expr.Expression.String()
+	}
+	if m.Type == MetricTypeInfo {
+		valueName = "none"
 	}
 
 	attrs := []slog.Attr{
 		slog.String("prometheus_name", m.PrometheusName),
 		slog.String("otel_name", m.OTELName),
 		slog.String("type", string(m.Type)),
+		slog.String("value_type", string(m.ValueType)),
 		slog.String("value", valueName),
 	}
 
+	// Add read mode for metrics reading the value
+	if m.Read != "" {
+		attrs = append(attrs, slog.String("read", string(m.Read)))
+	}
+
+	// Add bucket layout for histograms
+	if m.Type == MetricTypeHistogram {
+		attrs = append(attrs, slog.String("histogram_mode", string(m.Histogram.Mode)))
+		switch m.Histogram.Mode {
+		case HistogramModeExponential:
+			attrs = append(attrs,
+				slog.Int("scale", m.Histogram.Scale),
+				slog.Int("max_buckets", m.Histogram.MaxBuckets))
+		default:
+			attrs = append(attrs, slog.String("buckets", fmt.Sprintf("%g", m.Histogram.Buckets)))
+		}
+	}
+
+	// Add quantiles and window for summaries
+	if m.Type == MetricTypeSummary {
+		attrs = append(attrs,
+			slog.String("quantiles", fmt.Sprintf("%g", m.Summary.Quantiles)),
+			slog.Int("window", m.Summary.Window))
+	}
+
+	// Add states for statesets
+	if m.Type == MetricTypeStateSet {
+		mode := "value"
+		if m.StateSet.Transitions != nil {
+			mode = "transitions"
+		}
+		attrs = append(attrs,
+			slog.String("states", fmt.Sprintf("%s", m.StateSet.States)),
+			slog.String("state_selection", mode))
+	}
+
 	// Add attributes as sorted key=value pairs if present
 	if len(m.Attributes) > 0 {
 		attrKeys := make([]string, 0, len(m.Attributes))
@@ -1,11 +1,15 @@
 package config
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // SettingsConfig holds general application settings.
 type SettingsConfig struct {
 	Seed            *uint64
 	InternalMetrics InternalMetricsConfig
+	Time            TimeConfig
 }
 
 // InternalMetricsConfig controls otelbox's self-monitoring metrics.
@@ -14,6 +18,24 @@ type InternalMetricsConfig struct {
 	Format  NamingFormat
 }
 
+// TimeConfig controls the time base of the simulation.
+// The zero value runs in real time.
+type TimeConfig struct {
+	Speed float64   // Virtual seconds per real second, 0 runs as fast as possible
+	Start time.Time // Virtual time at startup, zero for real time
+	End   time.Time // Virtual time to stop at, zero runs until shutdown
+}
+
+// Virtual reports whether the simulation runs on virtual time.
+func (t TimeConfig) Virtual() bool {
+	return !t.Start.IsZero()
+}
+
+// MaxSpeed reports whether virtual time advances as fast as possible.
+func (t TimeConfig) MaxSpeed() bool {
+	return t.Virtual() && t.Speed == 0
+}
+
 // NamingFormat defines the naming convention for internal metrics.
 type NamingFormat string
 
@@ -39,8 +61,20 @@ func (s *SettingsConfig) Validate() error {
 	// Validate format value
 	switch s.InternalMetrics.Format {
 	case NamingFormatNative, NamingFormatUnderscore, NamingFormatDot:
-		return nil
 	default:
 		return fmt.Errorf("invalid naming format: %s (must be native, underscore, or dot)", s.InternalMetrics.Format)
 	}
+
+	// Validate time
+	if s.Time.Virtual() {
+		if s.Time.Speed < 0 {
+			return fmt.Errorf("invalid time speed: %g (must be positive or max)", s.Time.Speed)
+		}
+		if !s.Time.End.IsZero() && !s.Time.End.After(s.Time.Start) {
+			return fmt.Errorf("time end %s must be after start %s",
+				s.Time.End.Format(time.RFC3339), s.Time.Start.Format(time.RFC3339))
+		}
+	}
+
+	return nil
 }
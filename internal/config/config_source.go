@@ -1,16 +1,95 @@
 package config
 
-import "log/slog"
+import (
+	"log/slog"
+	"time"
+)
 
 // SourceConfig defines a fully resolved source with embedded clock
 type SourceConfig struct {
 	Type     string
 	Clock    ClockConfig
 	ClockRef *string // Instance name if clock is shared
-	Min      int
-	Max      int
+	Min      float64
+	Max      float64
+
+	// Waveform parameters (sine, cosine)
+	Amplitude float64
+	Offset    float64
+	Period    time.Duration
+	Phase     time.Duration
+
+	// Random walk parameters (random_walk, bounded by Min/Max)
+	Start            float64
+	Step             float64
+	StepDistribution string
+	Drift            float64
+	Boundary         string
+
+	// Distribution parameters (normal, exponential, poisson, lognormal)
+	Mean   float64
+	Stddev float64
+	Lambda float64
+	Mu     float64
+	Sigma  float64
+
+	// Replay parameters (replay)
+	File    string
+	Format  string
+	Loop    bool
+	Samples []float64 // Read from File during resolution
+
+	// Load profile multiplying emitted values, nil if unset
+	Profile *ProfileConfig
+}
+
+// ProfileConfig defines a time-of-day and day-of-week load profile.
+// Emitted values are multiplied by the hour factor and the day factor.
+type ProfileConfig struct {
+	Hours         []float64 // 24 factors, hour 0 first, nil multiplies by 1
+	Days          []float64 // 7 factors, Monday first, nil multiplies by 1
+	Interpolation string    // step or linear
+	Timezone      string    // Local time if empty
+}
+
+// Location returns the time zone of the profile.
+// Falls back to local time if the timezone is unset or invalid.
+func (p ProfileConfig) Location() *time.Location {
+	loc, err := loadLocation(p.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// LogValue implements slog.LogValuer for structured logging
+func (p ProfileConfig) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("hours", p.Hours),
+		slog.Any("days", p.Days),
+		slog.String("interpolation", p.Interpolation),
+		slog.String("timezone", p.Location().String()),
+	)
 }
 
+const (
+	// Random walk step distributions
+	StepDistributionUniform = "uniform"
+	StepDistributionNormal  = "normal"
+
+	// Random walk boundary behaviors
+	BoundaryClamp   = "clamp"
+	BoundaryReflect = "reflect"
+
+	// Replay file formats
+	ReplayFormatCSV    = "csv"
+	ReplayFormatNDJSON = "ndjson"
+
+	// Profile interpolations between table entries
+	ProfileInterpolationStep   = "step"
+	ProfileInterpolationLinear = "linear"
+)
+
 // LogValue implements slog.LogValuer for structured logging
 func (s SourceConfig) LogValue() slog.Value {
 	clockName := "inline"
@@ -21,8 +100,55 @@ func (s SourceConfig) LogValue() slog.Value {
 	attrs := []slog.Attr{
 		slog.String("type", s.Type),
 		slog.String("clock", clockName),
-		slog.Int("min", s.Min),
-		slog.Int("max", s.Max),
 	}
+
+	// Only log parameters relevant to the source type
+	switch s.Type {
+	case "sine", "cosine":
+		attrs = append(attrs,
+			slog.Float64("amplitude", s.Amplitude),
+			slog.Float64("offset", s.Offset),
+			slog.Duration("period", s.Period),
+			slog.Duration("phase", s.Phase),
+		)
+	case "random_walk":
+		attrs = append(attrs,
+			slog.Float64("min", s.Min),
+			slog.Float64("max", s.Max),
+			slog.Float64("start", s.Start),
+			slog.Float64("step", s.Step),
+			slog.String("step_distribution", s.StepDistribution),
+			slog.Float64("drift", s.Drift),
+			slog.String("boundary", s.Boundary),
+		)
+	case "normal":
+		attrs = append(attrs,
+			slog.Float64("mean", s.Mean),
+			slog.Float64("stddev", s.Stddev),
+		)
+	case "exponential", "poisson":
+		attrs = append(attrs, slog.Float64("lambda", s.Lambda))
+	case "lognormal":
+		attrs = append(attrs,
+			slog.Float64("mu", s.Mu),
+			slog.Float64("sigma", s.Sigma),
+		)
+	case "replay":
+		attrs = append(attrs,
+			slog.String("file", s.File),
+			slog.String("format", s.Format),
+			slog.Bool("loop", s.Loop),
+		)
+	default:
+		attrs = append(attrs,
+			slog.Float64("min", s.Min),
+			slog.Float64("max", s.Max),
+		)
+	}
+
+	if s.Profile != nil {
+		attrs = append(attrs, slog.Any("profile", *s.Profile))
+	}
+
 	return slog.GroupValue(attrs...)
 }
@@ -4,14 +4,210 @@ import (
 	"fmt"
 	"log/slog"
 	"strings"
+	"time"
+
+	"github.com/neox5/otelbox/internal/expr"
 )
 
 // ValueConfig defines a fully resolved value with embedded components.
+// Exactly one of Source, Derive or Expr is set.
 type ValueConfig struct {
 	Source     SourceConfig
-	SourceRef  *string // Instance name if source is shared
+	SourceRef  *string       // Instance name if source is shared
+	ValueRef   *string       // Instance name if value is shared
+	Derive     *DeriveConfig // Combination of instances instead of a source
+	Expr       *ExprConfig   // Expression over instances instead of a source
 	Transforms []TransformConfig
 	Reset      ResetConfig
+	Restart    *RestartConfig // Simulated process restarts, nil if disabled
+}
+
+// ResetType defines when a value returns to its reset value
+type ResetType string
+
+const (
+	ResetOnRead     ResetType = "on_read"     // Every read by an exporter
+	ResetOnInterval ResetType = "on_interval" // Every interval
+	ResetOnTick     ResetType = "on_tick"     // Every tick of a clock
+	ResetAtValue    ResetType = "at_value"    // Wraps once the threshold is reached
+)
+
+// ResetConfig defines a fully resolved reset behavior
+type ResetConfig struct {
+	Type      ResetType
+	Value     float64     // State after reset
+	Clock     ClockConfig // on_interval and on_tick, periodic for on_interval
+	ClockRef  *string     // Instance name if the reset clock is shared
+	Threshold float64     // at_value only
+}
+
+// OnClock reports whether a clock triggers the reset
+func (r ResetConfig) OnClock() bool {
+	return r.Type == ResetOnInterval || r.Type == ResetOnTick
+}
+
+// String returns the reset type with its parameters, e.g. "on_interval:1m0s"
+func (r ResetConfig) String() string {
+	desc := string(r.Type)
+	switch r.Type {
+	case ResetOnInterval:
+		desc += ":" + r.Clock.Interval.String()
+	case ResetOnTick:
+		clockName := "<inline>"
+		if r.ClockRef != nil {
+			clockName = *r.ClockRef
+		}
+		desc += ":" + clockName
+	case ResetAtValue:
+		desc += ":" + formatFloat(&r.Threshold)
+	}
+	if r.Value != 0 {
+		desc += ":" + formatFloat(&r.Value)
+	}
+	return desc
+}
+
+// RestartConfig defines simulated restarts of the process owning a value.
+// A restart resets the value and starts a new lifetime, which exporters
+// signal as created (Prometheus) or start (OTEL) timestamp.
+type RestartConfig struct {
+	Clock       ClockConfig // Ticks on which a restart may happen, periodic for interval
+	ClockRef    *string     // Instance name if the restart clock is shared
+	Probability float64     // Chance to restart per tick, 1 restarts on every tick
+	Value       float64     // State after restart
+}
+
+// String returns the restart clock with probability, e.g. "1h0m0s:1"
+func (r RestartConfig) String() string {
+	desc := r.Clock.Interval.String()
+	if r.ClockRef != nil {
+		desc = *r.ClockRef
+	}
+	desc += ":" + formatFloat(&r.Probability)
+	if r.Value != 0 {
+		desc += ":" + formatFloat(&r.Value)
+	}
+	return desc
+}
+
+// ResetByClock reports whether a clock resets or restarts the value.
+// Reads then never reset the value.
+func (v ValueConfig) ResetByClock() bool {
+	return v.Reset.OnClock() || v.Restart != nil
+}
+
+// DeriveOp defines how derive inputs are combined
+type DeriveOp string
+
+const (
+	DeriveOpSum         DeriveOp = "sum"
+	DeriveOpDiff        DeriveOp = "diff"
+	DeriveOpRatio       DeriveOp = "ratio"
+	DeriveOpMin         DeriveOp = "min"
+	DeriveOpMax         DeriveOp = "max"
+	DeriveOpWeightedSum DeriveOp = "weighted_sum"
+)
+
+// DeriveConfig defines a value combined from several instances
+type DeriveConfig struct {
+	Op      DeriveOp
+	Inputs  []DeriveInput
+	Weights []float64 // weighted_sum only, one per input
+}
+
+// DeriveInput is a resolved derive input, exactly one of Source or Value is set
+type DeriveInput struct {
+	Name   string
+	Source *SourceConfig // Source instance
+	Value  *ValueConfig  // Value instance
+}
+
+// ExprConfig defines a value computed by an expression over instances
+type ExprConfig struct {
+	Expression *expr.Expression
+	Inputs     []DeriveInput // One per Expression.Names entry
+}
+
+// String returns the operation with its inputs, e.g. "diff(total used)"
+func (d DeriveConfig) String() string {
+	names := make([]string, len(d.Inputs))
+	for i, input := range d.Inputs {
+		names[i] = input.Name
+	}
+	return fmt.Sprintf("%s(%s)", d.Op, strings.Join(names, " "))
+}
+
+// SharedClock reports whether all inputs are driven by one clock instance.
+// Their updates are then paired tick by tick.
+func (d DeriveConfig) SharedClock() bool {
+	return inputsClock(d.Inputs) != ""
+}
+
+// SharedClock reports whether all inputs are driven by one clock instance.
+func (e ExprConfig) SharedClock() bool {
+	return inputsClock(e.Inputs) != ""
+}
+
+// inputsClock returns the clock driving all inputs, empty if they differ.
+func inputsClock(inputs []DeriveInput) string {
+	var clock string
+	for i, input := range inputs {
+		inputClock := input.clock()
+		if inputClock == "" || (i > 0 && inputClock != clock) {
+			return ""
+		}
+		clock = inputClock
+	}
+	return clock
+}
+
+// clock identifies the clock driving the input. Inline clocks are identified
+// by the instance owning them, derived values by the clock shared by their inputs.
+func (input DeriveInput) clock() string {
+	if input.Source != nil {
+		return sourceClock(*input.Source, "source:"+input.Name)
+	}
+	value := input.Value
+	switch {
+	case value.Derive != nil:
+		return inputsClock(value.Derive.Inputs)
+	case value.Expr != nil:
+		return inputsClock(value.Expr.Inputs)
+	case value.SourceRef != nil:
+		return sourceClock(value.Source, "source:"+*value.SourceRef)
+	default:
+		return sourceClock(value.Source, "value:"+input.Name)
+	}
+}
+
+// sourceClock identifies the clock of a source, owner if the clock is inline.
+func sourceClock(source SourceConfig, owner string) string {
+	if source.ClockRef != nil {
+		return "clock:" + *source.ClockRef
+	}
+	return owner
+}
+
+// Interval returns the nominal interval between updates, the mean for random clocks.
+// Derived and expression values update with their inputs, the first input is used.
+func (v ValueConfig) Interval() time.Duration {
+	var inputs []DeriveInput
+	switch {
+	case v.Derive != nil:
+		inputs = v.Derive.Inputs
+	case v.Expr != nil:
+		inputs = v.Expr.Inputs
+	default:
+		return v.Source.Clock.MeanInterval()
+	}
+	if len(inputs) == 0 {
+		return 0
+	}
+	input := inputs[0]
+	if input.Source != nil {
+		return input.Source.Clock.MeanInterval()
+	}
+	return input.Value.Interval()
 }
 
 // LogValue implements slog.LogValuer for structured logging
@@ -20,11 +216,17 @@ func (v ValueConfig) LogValue() slog.Value {
 	if v.SourceRef != nil {
 		sourceName = "instance:" + *v.SourceRef
 	}
+	if v.Derive != nil {
+		sourceName = "derive:" + v.Derive.String()
+	}
+	if v.Expr != nil {
+		sourceName = "expr:" + v.Expr.Expression.String()
+	}
 
 	// Format transforms as array
 	transformNames := make([]string, len(v.Transforms))
 	for i, t := range v.Transforms {
-		transformNames[i] = t.Type
+		transformNames[i] = t.String()
 	}
 
 	attrs := []slog.Attr{
@@ -34,11 +236,10 @@ func (v ValueConfig) LogValue() slog.Value {
 
 	// Add reset info if configured
 	if v.Reset.Type != "" {
-		resetDesc := v.Reset.Type
-		if v.Reset.Value != 0 {
-			resetDesc = fmt.Sprintf("%s:%d", v.Reset.Type, v.Reset.Value)
-		}
-		attrs = append(attrs, slog.String("reset", resetDesc))
+		attrs = append(attrs, slog.String("reset", v.Reset.String()))
+	}
+	if v.Restart != nil {
+		attrs = append(attrs, slog.String("restart", v.Restart.String()))
 	}
 
 	return slog.GroupValue(attrs...)
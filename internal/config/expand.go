@@ -151,6 +151,12 @@ func Expand(raw *RawConfig) error {
 		return fmt.Errorf("failed to expand instance values: %w", err)
 	}
 
+	// Expand template metrics
+	raw.Templates.Metrics, err = expander.ExpandMetrics(raw.Templates.Metrics)
+	if err != nil {
+		return fmt.Errorf("failed to expand template metrics: %w", err)
+	}
+
 	// Expand metrics
 	raw.Metrics, err = expander.ExpandMetrics(raw.Metrics)
 	if err != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"go.yaml.in/yaml/v4"
 )
@@ -27,5 +28,10 @@ func Parse(path string) (*RawConfig, error) {
 		return nil, err
 	}
 
+	raw.Dir, err = filepath.Abs(filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve config directory: %w", err)
+	}
+
 	return &raw, nil
 }
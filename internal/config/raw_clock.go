@@ -9,6 +9,10 @@ type RawClockReference struct {
 	Template string        `yaml:"template,omitempty"`
 	Type     *string       `yaml:"type,omitempty"`
 	Interval time.Duration `yaml:"interval,omitempty"`
+	Jitter   time.Duration `yaml:"jitter,omitempty"`   // jitter only
+	Rate     float64       `yaml:"rate,omitempty"`     // poisson only, ticks per second
+	Schedule string        `yaml:"schedule,omitempty"` // cron only
+	Timezone string        `yaml:"timezone,omitempty"` // cron only, local time if empty
 }
 
 // DeepCopy creates an independent copy of the clock reference
@@ -38,6 +42,12 @@ func (c *RawClockReference) FindPlaceholders() []string {
 	for _, name := range extractPlaceholderNames(c.Template) {
 		found[name] = true
 	}
+	for _, name := range extractPlaceholderNames(c.Schedule) {
+		found[name] = true
+	}
+	for _, name := range extractPlaceholderNames(c.Timezone) {
+		found[name] = true
+	}
 
 	// Convert to slice
 	result := make([]string, 0, len(found))
@@ -52,4 +62,6 @@ func (c *RawClockReference) SubstitutePlaceholders(iteratorValues map[string]str
 	c.Name = substitutePlaceholders(c.Name, iteratorValues)
 	c.Instance = substitutePlaceholders(c.Instance, iteratorValues)
 	c.Template = substitutePlaceholders(c.Template, iteratorValues)
+	c.Schedule = substitutePlaceholders(c.Schedule, iteratorValues)
+	c.Timezone = substitutePlaceholders(c.Timezone, iteratorValues)
 }
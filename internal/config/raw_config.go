@@ -8,6 +8,9 @@ type RawConfig struct {
 	Metrics   []RawMetricConfig `yaml:"metrics"`
 	Export    RawExportConfig   `yaml:"export"`
 	Settings  RawSettingsConfig `yaml:"settings"`
+
+	// Directory of the configuration file, relative file paths resolve against it
+	Dir string `yaml:"-"`
 }
 
 // RawTemplates holds all template definitions
@@ -15,6 +18,7 @@ type RawTemplates struct {
 	Clocks  []RawClockReference  `yaml:"clocks,omitempty"`
 	Sources []RawSourceReference `yaml:"sources,omitempty"`
 	Values  []RawValueReference  `yaml:"values,omitempty"`
+	Metrics []RawMetricConfig    `yaml:"metrics,omitempty"`
 }
 
 // RawInstances holds all instance definitions
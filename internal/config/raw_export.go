@@ -21,13 +21,27 @@ type RawPrometheusExportConfig struct {
 
 // RawOTELExportConfig defines OTEL push settings
 type RawOTELExportConfig struct {
-	Enabled   bool              `yaml:"enabled"`
-	Transport string            `yaml:"transport"`
-	Host      string            `yaml:"host"`
-	Port      int               `yaml:"port"`
-	Interval  RawIntervalConfig `yaml:"interval"`
-	Resource  map[string]string `yaml:"resource,omitempty"`
-	Headers   map[string]string `yaml:"headers,omitempty"`
+	Enabled   bool               `yaml:"enabled"`
+	Transport string             `yaml:"transport"`
+	Host      string             `yaml:"host"`
+	Port      int                `yaml:"port"`
+	Interval  RawIntervalConfig  `yaml:"interval"`
+	Resource  map[string]string  `yaml:"resource,omitempty"`
+	Headers   map[string]string  `yaml:"headers,omitempty"`
+	File      *RawOTELFileConfig `yaml:"file,omitempty"`
+}
+
+// RawOTELFileConfig defines the output of the file transport
+type RawOTELFileConfig struct {
+	Path     string             `yaml:"path"`
+	Rotation *RawRotationConfig `yaml:"rotation,omitempty"`
+}
+
+// RawRotationConfig defines when output files are rotated
+type RawRotationConfig struct {
+	MaxMegabytes int           `yaml:"max_megabytes"`
+	Interval     time.Duration `yaml:"interval"`
+	MaxBackups   int           `yaml:"max_backups"`
 }
 
 // RawIntervalConfig defines read and push intervals for OTEL
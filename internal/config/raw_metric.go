@@ -1,14 +1,58 @@
 package config
 
-import "go.yaml.in/yaml/v4"
+import (
+	"maps"
+
+	"go.yaml.in/yaml/v4"
+)
 
 // RawMetricConfig with polymorphic value field
+// Metric templates use the same structure, the simple name is the template name.
 type RawMetricConfig struct {
-	Name        RawMetricNameConfig `yaml:"name"`
-	Type        string              `yaml:"type"`
-	Description string              `yaml:"description"`
-	Value       RawValueReference   `yaml:"value"`
-	Attributes  map[string]string   `yaml:"attributes,omitempty"`
+	Name           RawMetricNameConfig `yaml:"name"`
+	Template       string              `yaml:"template,omitempty"`
+	Type           string              `yaml:"type"`
+	ValueType      string              `yaml:"value_type,omitempty"`
+	Description    string              `yaml:"description"`
+	Value          RawValueReference   `yaml:"value"`
+	Read           string              `yaml:"read,omitempty"` // consume (default) or peek
+	Histogram      *RawHistogramConfig `yaml:"histogram,omitempty"`
+	Summary        *RawSummaryConfig   `yaml:"summary,omitempty"`
+	StateSet       *RawStateSetConfig  `yaml:"stateset,omitempty"`
+	Attributes     map[string]string   `yaml:"attributes,omitempty"`
+	AttributesMode string              `yaml:"attributes_mode,omitempty"` // merge (default) or replace
+}
+
+// Attribute modes of metrics referencing a metric template
+const (
+	AttributesModeMerge   = "merge"   // Template attributes extended and overridden by the metric
+	AttributesModeReplace = "replace" // Metric attributes replace the template attributes
+)
+
+// RawHistogramConfig holds histogram aggregation settings
+type RawHistogramConfig struct {
+	Mode       string    `yaml:"mode,omitempty"`
+	Buckets    []float64 `yaml:"buckets,omitempty"`
+	Scale      *int      `yaml:"scale,omitempty"`
+	MaxBuckets *int      `yaml:"max_buckets,omitempty"`
+}
+
+// DeepCopy creates an independent copy of the histogram config
+func (h RawHistogramConfig) DeepCopy() RawHistogramConfig {
+	clone := h
+	if h.Buckets != nil {
+		clone.Buckets = make([]float64, len(h.Buckets))
+		copy(clone.Buckets, h.Buckets)
+	}
+	if h.Scale != nil {
+		v := *h.Scale
+		clone.Scale = &v
+	}
+	if h.MaxBuckets != nil {
+		v := *h.MaxBuckets
+		clone.MaxBuckets = &v
+	}
+	return clone
 }
 
 // DeepCopy creates an independent copy of the metric config
@@ -21,6 +65,24 @@ func (m RawMetricConfig) DeepCopy() RawMetricConfig {
 	// Deep copy value reference
 	clone.Value = m.Value.DeepCopy()
 
+	// Deep copy histogram config
+	if m.Histogram != nil {
+		histogram := m.Histogram.DeepCopy()
+		clone.Histogram = &histogram
+	}
+
+	// Deep copy summary config
+	if m.Summary != nil {
+		summary := m.Summary.DeepCopy()
+		clone.Summary = &summary
+	}
+
+	// Deep copy stateset config
+	if m.StateSet != nil {
+		stateSet := m.StateSet.DeepCopy()
+		clone.StateSet = &stateSet
+	}
+
 	// Deep copy attributes map
 	if len(m.Attributes) > 0 {
 		clone.Attributes = make(map[string]string, len(m.Attributes))
@@ -32,6 +94,50 @@ func (m RawMetricConfig) DeepCopy() RawMetricConfig {
 	return clone
 }
 
+// RawSummaryConfig holds summary aggregation settings
+type RawSummaryConfig struct {
+	Quantiles []float64 `yaml:"quantiles,omitempty"`
+	Window    *int      `yaml:"window,omitempty"`
+}
+
+// DeepCopy creates an independent copy of the summary config
+func (s RawSummaryConfig) DeepCopy() RawSummaryConfig {
+	clone := s
+	if s.Quantiles != nil {
+		clone.Quantiles = make([]float64, len(s.Quantiles))
+		copy(clone.Quantiles, s.Quantiles)
+	}
+	if s.Window != nil {
+		v := *s.Window
+		clone.Window = &v
+	}
+	return clone
+}
+
+// RawStateSetConfig holds the states of a stateset metric
+type RawStateSetConfig struct {
+	States      []string                      `yaml:"states"`
+	Label       string                        `yaml:"label,omitempty"`
+	Initial     string                        `yaml:"initial,omitempty"`
+	Transitions map[string]map[string]float64 `yaml:"transitions,omitempty"`
+}
+
+// DeepCopy creates an independent copy of the stateset config
+func (s RawStateSetConfig) DeepCopy() RawStateSetConfig {
+	clone := s
+	if s.States != nil {
+		clone.States = make([]string, len(s.States))
+		copy(clone.States, s.States)
+	}
+	if s.Transitions != nil {
+		clone.Transitions = make(map[string]map[string]float64, len(s.Transitions))
+		for from, weights := range s.Transitions {
+			clone.Transitions[from] = maps.Clone(weights)
+		}
+	}
+	return clone
+}
+
 // FindPlaceholders implements expandable for RawMetricConfig
 func (m *RawMetricConfig) FindPlaceholders() []string {
 	found := make(map[string]bool)
@@ -41,6 +147,11 @@ func (m *RawMetricConfig) FindPlaceholders() []string {
 		found[name] = true
 	}
 
+	// Scan template reference
+	for _, name := range extractPlaceholderNames(m.Template) {
+		found[name] = true
+	}
+
 	// Scan attribute keys and values
 	for key, value := range m.Attributes {
 		for _, name := range extractPlaceholderNames(key) {
@@ -66,8 +177,9 @@ func (m *RawMetricConfig) FindPlaceholders() []string {
 
 // SubstitutePlaceholders implements expandable for RawMetricConfig
 func (m *RawMetricConfig) SubstitutePlaceholders(iteratorValues map[string]string) {
-	// Substitute in name
+	// Substitute in name and template reference
 	m.Name.SubstitutePlaceholders(iteratorValues)
+	m.Template = substitutePlaceholders(m.Template, iteratorValues)
 
 	// Substitute in attributes - both keys and values
 	if len(m.Attributes) > 0 {
@@ -4,6 +4,7 @@ package config
 type RawSettingsConfig struct {
 	Seed            *uint64                  `yaml:"seed,omitempty"`
 	InternalMetrics RawInternalMetricsConfig `yaml:"internal_metrics"`
+	Time            RawTimeConfig            `yaml:"time"`
 }
 
 // RawInternalMetricsConfig controls otelbox's self-monitoring metrics
@@ -11,3 +12,10 @@ type RawInternalMetricsConfig struct {
 	Enabled bool   `yaml:"enabled"`
 	Format  string `yaml:"format"`
 }
+
+// RawTimeConfig controls the time base of the simulation
+type RawTimeConfig struct {
+	Speed string `yaml:"speed,omitempty"` // Speed factor or "max"
+	Start string `yaml:"start,omitempty"` // RFC 3339 timestamp or offset from now
+	End   string `yaml:"end,omitempty"`   // RFC 3339 timestamp or offset from now
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"slices"
+	"time"
+)
+
 // RawSourceReference handles polymorphic source field (instance/template/inline)
 type RawSourceReference struct {
 	Name     string             `yaml:"name,omitempty"` // Only used in templates/instances arrays
@@ -7,8 +12,44 @@ type RawSourceReference struct {
 	Template string             `yaml:"template,omitempty"`
 	Type     *string            `yaml:"type,omitempty"`
 	Clock    *RawClockReference `yaml:"clock,omitempty"`
-	Min      *int               `yaml:"min,omitempty"`
-	Max      *int               `yaml:"max,omitempty"`
+	Min      *float64           `yaml:"min,omitempty"`
+	Max      *float64           `yaml:"max,omitempty"`
+
+	// Waveform parameters (sine, cosine)
+	Amplitude *float64      `yaml:"amplitude,omitempty"`
+	Offset    *float64      `yaml:"offset,omitempty"`
+	Period    time.Duration `yaml:"period,omitempty"`
+	Phase     time.Duration `yaml:"phase,omitempty"`
+
+	// Random walk parameters (random_walk, bounded by min/max)
+	Start            *float64 `yaml:"start,omitempty"`
+	Step             *float64 `yaml:"step,omitempty"`
+	StepDistribution string   `yaml:"step_distribution,omitempty"`
+	Drift            *float64 `yaml:"drift,omitempty"`
+	Boundary         string   `yaml:"boundary,omitempty"`
+
+	// Distribution parameters (normal, exponential, poisson, lognormal)
+	Mean   *float64 `yaml:"mean,omitempty"`
+	Stddev *float64 `yaml:"stddev,omitempty"`
+	Lambda *float64 `yaml:"lambda,omitempty"`
+	Mu     *float64 `yaml:"mu,omitempty"`
+	Sigma  *float64 `yaml:"sigma,omitempty"`
+
+	// Replay parameters (replay)
+	File   string `yaml:"file,omitempty"`
+	Format string `yaml:"format,omitempty"`
+	Loop   *bool  `yaml:"loop,omitempty"`
+
+	// Load profile multiplying emitted values (all types)
+	Profile *RawProfileConfig `yaml:"profile,omitempty"`
+}
+
+// RawProfileConfig defines a time-of-day and day-of-week load profile
+type RawProfileConfig struct {
+	Hours         []float64 `yaml:"hours,omitempty"` // 24 factors, hour 0 first
+	Days          []float64 `yaml:"days,omitempty"`  // 7 factors, Monday first
+	Interpolation string    `yaml:"interpolation,omitempty"`
+	Timezone      string    `yaml:"timezone,omitempty"`
 }
 
 // DeepCopy creates an independent copy of the source reference
@@ -31,15 +72,156 @@ func (s RawSourceReference) DeepCopy() RawSourceReference {
 		clone.Max = &maxCopy
 	}
 
+	if s.Amplitude != nil {
+		amplitudeCopy := *s.Amplitude
+		clone.Amplitude = &amplitudeCopy
+	}
+
+	if s.Offset != nil {
+		offsetCopy := *s.Offset
+		clone.Offset = &offsetCopy
+	}
+
+	if s.Start != nil {
+		startCopy := *s.Start
+		clone.Start = &startCopy
+	}
+
+	if s.Step != nil {
+		stepCopy := *s.Step
+		clone.Step = &stepCopy
+	}
+
+	if s.Drift != nil {
+		driftCopy := *s.Drift
+		clone.Drift = &driftCopy
+	}
+
+	if s.Mean != nil {
+		meanCopy := *s.Mean
+		clone.Mean = &meanCopy
+	}
+
+	if s.Stddev != nil {
+		stddevCopy := *s.Stddev
+		clone.Stddev = &stddevCopy
+	}
+
+	if s.Lambda != nil {
+		lambdaCopy := *s.Lambda
+		clone.Lambda = &lambdaCopy
+	}
+
+	if s.Mu != nil {
+		muCopy := *s.Mu
+		clone.Mu = &muCopy
+	}
+
+	if s.Sigma != nil {
+		sigmaCopy := *s.Sigma
+		clone.Sigma = &sigmaCopy
+	}
+
+	if s.Loop != nil {
+		loopCopy := *s.Loop
+		clone.Loop = &loopCopy
+	}
+
 	// Deep copy nested clock reference
 	if s.Clock != nil {
 		clockCopy := s.Clock.DeepCopy()
 		clone.Clock = &clockCopy
 	}
 
+	if s.Profile != nil {
+		profileCopy := *s.Profile
+		profileCopy.Hours = slices.Clone(s.Profile.Hours)
+		profileCopy.Days = slices.Clone(s.Profile.Days)
+		clone.Profile = &profileCopy
+	}
+
 	return clone
 }
 
+// hasParameters reports whether any type-specific parameter is set
+func (s *RawSourceReference) hasParameters() bool {
+	return s.Min != nil || s.Max != nil ||
+		s.Amplitude != nil || s.Offset != nil || s.Period != 0 || s.Phase != 0 ||
+		s.Start != nil || s.Step != nil || s.StepDistribution != "" || s.Drift != nil || s.Boundary != "" ||
+		s.Mean != nil || s.Stddev != nil || s.Lambda != nil || s.Mu != nil || s.Sigma != nil ||
+		s.File != "" || s.Format != "" || s.Loop != nil ||
+		s.Profile != nil
+}
+
+// applyParameters copies set type-specific parameters onto a resolved source
+func (s *RawSourceReference) applyParameters(dst *SourceConfig) {
+	if s.Min != nil {
+		dst.Min = *s.Min
+	}
+	if s.Max != nil {
+		dst.Max = *s.Max
+	}
+	if s.Amplitude != nil {
+		dst.Amplitude = *s.Amplitude
+	}
+	if s.Offset != nil {
+		dst.Offset = *s.Offset
+	}
+	if s.Period != 0 {
+		dst.Period = s.Period
+	}
+	if s.Phase != 0 {
+		dst.Phase = s.Phase
+	}
+	if s.Start != nil {
+		dst.Start = *s.Start
+	}
+	if s.Step != nil {
+		dst.Step = *s.Step
+	}
+	if s.StepDistribution != "" {
+		dst.StepDistribution = s.StepDistribution
+	}
+	if s.Drift != nil {
+		dst.Drift = *s.Drift
+	}
+	if s.Boundary != "" {
+		dst.Boundary = s.Boundary
+	}
+	if s.Mean != nil {
+		dst.Mean = *s.Mean
+	}
+	if s.Stddev != nil {
+		dst.Stddev = *s.Stddev
+	}
+	if s.Lambda != nil {
+		dst.Lambda = *s.Lambda
+	}
+	if s.Mu != nil {
+		dst.Mu = *s.Mu
+	}
+	if s.Sigma != nil {
+		dst.Sigma = *s.Sigma
+	}
+	if s.File != "" {
+		dst.File = s.File
+	}
+	if s.Format != "" {
+		dst.Format = s.Format
+	}
+	if s.Loop != nil {
+		dst.Loop = *s.Loop
+	}
+	if s.Profile != nil {
+		dst.Profile = &ProfileConfig{
+			Hours:         slices.Clone(s.Profile.Hours),
+			Days:          slices.Clone(s.Profile.Days),
+			Interpolation: s.Profile.Interpolation,
+			Timezone:      s.Profile.Timezone,
+		}
+	}
+}
+
 // FindPlaceholders implements expandable for RawSourceReference
 func (s *RawSourceReference) FindPlaceholders() []string {
 	found := make(map[string]bool)
@@ -54,6 +236,14 @@ func (s *RawSourceReference) FindPlaceholders() []string {
 	for _, name := range extractPlaceholderNames(s.Template) {
 		found[name] = true
 	}
+	for _, name := range extractPlaceholderNames(s.File) {
+		found[name] = true
+	}
+	if s.Profile != nil {
+		for _, name := range extractPlaceholderNames(s.Profile.Timezone) {
+			found[name] = true
+		}
+	}
 
 	// Recursively scan nested clock
 	if s.Clock != nil {
@@ -75,6 +265,10 @@ func (s *RawSourceReference) SubstitutePlaceholders(iteratorValues map[string]st
 	s.Name = substitutePlaceholders(s.Name, iteratorValues)
 	s.Instance = substitutePlaceholders(s.Instance, iteratorValues)
 	s.Template = substitutePlaceholders(s.Template, iteratorValues)
+	s.File = substitutePlaceholders(s.File, iteratorValues)
+	if s.Profile != nil {
+		s.Profile.Timezone = substitutePlaceholders(s.Profile.Timezone, iteratorValues)
+	}
 
 	// Recursively substitute in nested clock
 	if s.Clock != nil {
@@ -1,6 +1,13 @@
 package config
 
-import "go.yaml.in/yaml/v4"
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"go.yaml.in/yaml/v4"
+)
 
 // RawValueReference handles polymorphic value field (instance/template/inline)
 type RawValueReference struct {
@@ -8,8 +15,11 @@ type RawValueReference struct {
 	Instance   string              `yaml:"instance,omitempty"`
 	Template   string              `yaml:"template,omitempty"`
 	Source     *RawSourceReference `yaml:"source,omitempty"`
+	Derive     *RawDeriveConfig    `yaml:"derive,omitempty"` // Alternative to source
+	Expr       string              `yaml:"expr,omitempty"`   // Alternative to source
 	Transforms []TransformConfig   `yaml:"transforms,omitempty"`
-	Reset      ResetConfig         `yaml:"reset,omitempty"`
+	Reset      RawResetConfig      `yaml:"reset,omitempty"`
+	Restart    *RawRestartConfig   `yaml:"restart,omitempty"`
 }
 
 // DeepCopy creates an independent copy of the value reference
@@ -22,17 +32,38 @@ func (v RawValueReference) DeepCopy() RawValueReference {
 		clone.Source = &sourceCopy
 	}
 
+	// Deep copy derive inputs and weights
+	if v.Derive != nil {
+		deriveCopy := v.Derive.DeepCopy()
+		clone.Derive = &deriveCopy
+	}
+
 	// Deep copy transforms slice
 	if len(v.Transforms) > 0 {
 		clone.Transforms = make([]TransformConfig, len(v.Transforms))
-		copy(clone.Transforms, v.Transforms)
+		for i, t := range v.Transforms {
+			clone.Transforms[i] = t.DeepCopy()
+		}
 	}
 
-	// Reset config is plain struct, no pointers to copy
+	// Deep copy reset clock and threshold
+	clone.Reset = v.Reset.DeepCopy()
+
+	// Deep copy restart config
+	if v.Restart != nil {
+		restartCopy := v.Restart.DeepCopy()
+		clone.Restart = &restartCopy
+	}
 
 	return clone
 }
 
+// isEmpty reports whether no value was configured
+func (v *RawValueReference) isEmpty() bool {
+	return v.Instance == "" && v.Template == "" && v.Source == nil && v.Derive == nil && v.Expr == "" &&
+		len(v.Transforms) == 0 && v.Reset.Type == "" && v.Restart == nil
+}
+
 // FindPlaceholders implements expandable for RawValueReference
 func (v *RawValueReference) FindPlaceholders() []string {
 	found := make(map[string]bool)
@@ -47,6 +78,9 @@ func (v *RawValueReference) FindPlaceholders() []string {
 	for _, name := range extractPlaceholderNames(v.Template) {
 		found[name] = true
 	}
+	for _, name := range extractPlaceholderNames(v.Expr) {
+		found[name] = true
+	}
 
 	// Recursively scan nested source
 	if v.Source != nil {
@@ -55,6 +89,27 @@ func (v *RawValueReference) FindPlaceholders() []string {
 		}
 	}
 
+	// Scan derive inputs
+	if v.Derive != nil {
+		for _, input := range v.Derive.Inputs {
+			for _, name := range extractPlaceholderNames(input) {
+				found[name] = true
+			}
+		}
+	}
+
+	// Recursively scan reset and restart clocks
+	if v.Reset.Clock != nil {
+		for _, name := range v.Reset.Clock.FindPlaceholders() {
+			found[name] = true
+		}
+	}
+	if v.Restart != nil && v.Restart.Clock != nil {
+		for _, name := range v.Restart.Clock.FindPlaceholders() {
+			found[name] = true
+		}
+	}
+
 	// Convert to slice
 	result := make([]string, 0, len(found))
 	for name := range found {
@@ -68,16 +123,93 @@ func (v *RawValueReference) SubstitutePlaceholders(iteratorValues map[string]str
 	v.Name = substitutePlaceholders(v.Name, iteratorValues)
 	v.Instance = substitutePlaceholders(v.Instance, iteratorValues)
 	v.Template = substitutePlaceholders(v.Template, iteratorValues)
+	v.Expr = substitutePlaceholders(v.Expr, iteratorValues)
 
 	// Recursively substitute in nested source
 	if v.Source != nil {
 		v.Source.SubstitutePlaceholders(iteratorValues)
 	}
+
+	// Substitute in derive inputs
+	if v.Derive != nil {
+		for i, input := range v.Derive.Inputs {
+			v.Derive.Inputs[i] = substitutePlaceholders(input, iteratorValues)
+		}
+	}
+
+	// Recursively substitute in reset and restart clocks
+	if v.Reset.Clock != nil {
+		v.Reset.Clock.SubstitutePlaceholders(iteratorValues)
+	}
+	if v.Restart != nil && v.Restart.Clock != nil {
+		v.Restart.Clock.SubstitutePlaceholders(iteratorValues)
+	}
+}
+
+// RawDeriveConfig combines updates of several source or value instances
+type RawDeriveConfig struct {
+	Op      string    `yaml:"op"`
+	Inputs  []string  `yaml:"inputs"`            // Source or value instance names
+	Weights []float64 `yaml:"weights,omitempty"` // weighted_sum only, one per input
 }
 
-// TransformConfig defines a transform operation
+// DeepCopy creates an independent copy of the derive config
+func (d RawDeriveConfig) DeepCopy() RawDeriveConfig {
+	clone := d
+	clone.Inputs = slices.Clone(d.Inputs)
+	clone.Weights = slices.Clone(d.Weights)
+	return clone
+}
+
+// TransformConfig defines a transform operation with type-specific parameters
 type TransformConfig struct {
-	Type string
+	Type   string
+	Factor *float64 // scale
+	Offset *float64 // offset
+	Min    *float64 // clamp
+	Max    *float64 // clamp
+	Step   *float64 // quantize
+	Window *int     // moving_average
+	Alpha  *float64 // ewma
+}
+
+// DeepCopy creates an independent copy of the transform config
+func (t TransformConfig) DeepCopy() TransformConfig {
+	clone := t
+	clone.Factor = copyFloat(t.Factor)
+	clone.Offset = copyFloat(t.Offset)
+	clone.Min = copyFloat(t.Min)
+	clone.Max = copyFloat(t.Max)
+	clone.Step = copyFloat(t.Step)
+	if t.Window != nil {
+		window := *t.Window
+		clone.Window = &window
+	}
+	clone.Alpha = copyFloat(t.Alpha)
+	return clone
+}
+
+// String returns the type with its parameters, e.g. "scale:512" or "clamp:0..100"
+func (t TransformConfig) String() string {
+	switch t.Type {
+	case "scale":
+		return fmt.Sprintf("%s:%s", t.Type, formatFloat(t.Factor))
+	case "offset":
+		return fmt.Sprintf("%s:%s", t.Type, formatFloat(t.Offset))
+	case "clamp":
+		return fmt.Sprintf("%s:%s..%s", t.Type, formatFloat(t.Min), formatFloat(t.Max))
+	case "quantize":
+		return fmt.Sprintf("%s:%s", t.Type, formatFloat(t.Step))
+	case "moving_average":
+		if t.Window == nil {
+			return t.Type + ":_"
+		}
+		return fmt.Sprintf("%s:%d", t.Type, *t.Window)
+	case "ewma":
+		return fmt.Sprintf("%s:%s", t.Type, formatFloat(t.Alpha))
+	default:
+		return t.Type
+	}
 }
 
 // UnmarshalYAML handles both string and object forms for transforms
@@ -91,42 +223,100 @@ func (t *TransformConfig) UnmarshalYAML(value *yaml.Node) error {
 
 	// Fall back to object form
 	type transformConfig struct {
-		Type string `yaml:"type"`
+		Type   string   `yaml:"type"`
+		Factor *float64 `yaml:"factor"`
+		Offset *float64 `yaml:"offset"`
+		Min    *float64 `yaml:"min"`
+		Max    *float64 `yaml:"max"`
+		Step   *float64 `yaml:"step"`
+		Window *int     `yaml:"window"`
+		Alpha  *float64 `yaml:"alpha"`
 	}
 	var full transformConfig
 	if err := value.Decode(&full); err != nil {
 		return err
 	}
-	t.Type = full.Type
+	*t = TransformConfig(full)
 	return nil
 }
 
-// ResetConfig defines reset behavior
-type ResetConfig struct {
-	Type  string
-	Value int
+// copyFloat returns an independent copy of an optional float
+func copyFloat(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	c := *v
+	return &c
+}
+
+// formatFloat formats an optional float, "_" if unset
+func formatFloat(v *float64) string {
+	if v == nil {
+		return "_"
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
+
+// RawResetConfig defines reset behavior
+type RawResetConfig struct {
+	Type      string
+	Value     float64            // State after reset
+	Interval  time.Duration      // on_interval only
+	Clock     *RawClockReference // on_tick only
+	Threshold *float64           // at_value only
+}
+
+// DeepCopy creates an independent copy of the reset config
+func (r RawResetConfig) DeepCopy() RawResetConfig {
+	clone := r
+	if r.Clock != nil {
+		clockCopy := r.Clock.DeepCopy()
+		clone.Clock = &clockCopy
+	}
+	clone.Threshold = copyFloat(r.Threshold)
+	return clone
 }
 
 // UnmarshalYAML handles both string and object forms for reset
-func (r *ResetConfig) UnmarshalYAML(value *yaml.Node) error {
+func (r *RawResetConfig) UnmarshalYAML(value *yaml.Node) error {
 	// Try string form first (shorthand)
 	var simple string
 	if err := value.Decode(&simple); err == nil {
-		r.Type = simple
-		r.Value = 0 // Default value for shorthand
+		*r = RawResetConfig{Type: simple} // Default value for shorthand
 		return nil
 	}
 
 	// Fall back to object form
 	type resetConfig struct {
-		Type  string `yaml:"type"`
-		Value int    `yaml:"value"`
+		Type      string             `yaml:"type"`
+		Value     float64            `yaml:"value"`
+		Interval  time.Duration      `yaml:"interval"`
+		Clock     *RawClockReference `yaml:"clock"`
+		Threshold *float64           `yaml:"threshold"`
 	}
 	var full resetConfig
 	if err := value.Decode(&full); err != nil {
 		return err
 	}
-	r.Type = full.Type
-	r.Value = full.Value
+	*r = RawResetConfig(full)
 	return nil
 }
+
+// RawRestartConfig defines simulated restarts of the process owning a value
+type RawRestartConfig struct {
+	Interval    time.Duration      `yaml:"interval,omitempty"`    // Alternative to clock
+	Clock       *RawClockReference `yaml:"clock,omitempty"`       // Alternative to interval
+	Probability *float64           `yaml:"probability,omitempty"` // Chance per tick (default: 1)
+	Value       float64            `yaml:"value,omitempty"`       // State after restart
+}
+
+// DeepCopy creates an independent copy of the restart config
+func (r RawRestartConfig) DeepCopy() RawRestartConfig {
+	clone := r
+	if r.Clock != nil {
+		clockCopy := r.Clock.DeepCopy()
+		clone.Clock = &clockCopy
+	}
+	clone.Probability = copyFloat(r.Probability)
+	return clone
+}
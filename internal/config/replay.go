@@ -0,0 +1,131 @@
+package config
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// loadReplaySamples reads all samples from the replay file of a source.
+func loadReplaySamples(path, format string) ([]float64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open replay file: %w", err)
+	}
+	defer f.Close()
+
+	var samples []float64
+	switch format {
+	case ReplayFormatCSV:
+		samples, err = parseReplayCSV(f)
+	case ReplayFormatNDJSON:
+		samples, err = parseReplayNDJSON(f)
+	default:
+		return nil, fmt.Errorf("unknown replay format: %s", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("replay file %q: %w", path, err)
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("replay file %q has no samples", path)
+	}
+
+	return samples, nil
+}
+
+// parseReplayCSV reads samples from CSV rows of "value" or "timestamp,value".
+// An optional header row selects the "value" column, otherwise the last column is used.
+func parseReplayCSV(r io.Reader) ([]float64, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	reader.Comment = '#'
+
+	var samples []float64
+	column := -1 // -1 selects the last column
+
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Header row - only accepted as first row
+		if row == 1 && !isNumeric(record[len(record)-1]) {
+			if idx := slices.Index(record, "value"); idx >= 0 {
+				column = idx
+			}
+			continue
+		}
+
+		field := record[len(record)-1]
+		if column >= 0 {
+			if column >= len(record) {
+				return nil, fmt.Errorf("row %d: missing value column", row)
+			}
+			field = record[column]
+		}
+
+		value, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: invalid value %q", row, field)
+		}
+		samples = append(samples, value)
+	}
+
+	return samples, nil
+}
+
+// parseReplayNDJSON reads samples from lines holding either a bare number
+// or an object with a "value" field (other fields such as timestamps are ignored).
+func parseReplayNDJSON(r io.Reader) ([]float64, error) {
+	scanner := bufio.NewScanner(r)
+
+	var samples []float64
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var value float64
+		if err := json.Unmarshal([]byte(text), &value); err == nil {
+			samples = append(samples, value)
+			continue
+		}
+
+		var record struct {
+			Value *float64 `json:"value"`
+		}
+		if err := json.Unmarshal([]byte(text), &record); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if record.Value == nil {
+			return nil, fmt.Errorf("line %d: missing value field", line)
+		}
+		samples = append(samples, *record.Value)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return samples, nil
+}
+
+// isNumeric reports whether s parses as a float.
+func isNumeric(s string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return err == nil
+}
@@ -16,7 +16,7 @@ type Resolver struct {
 	templateClocks  map[string]ClockConfig
 	templateSources map[string]SourceConfig
 	templateValues  map[string]ValueConfig
-	templateMetrics map[string]MetricConfig
+	templateMetrics map[string]RawMetricConfig // Merged with parent template, resolved per metric
 
 	// Resolved instances (kept in final config)
 	instanceClocks  map[string]ClockConfig
@@ -32,7 +32,7 @@ func newResolver(raw *RawConfig) *Resolver {
 		templateClocks:  make(map[string]ClockConfig),
 		templateSources: make(map[string]SourceConfig),
 		templateValues:  make(map[string]ValueConfig),
-		templateMetrics: make(map[string]MetricConfig),
+		templateMetrics: make(map[string]RawMetricConfig),
 		instanceClocks:  make(map[string]ClockConfig),
 		instanceSources: make(map[string]SourceConfig),
 		instanceValues:  make(map[string]ValueConfig),
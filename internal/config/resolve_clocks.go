@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"log/slog"
+
+	"github.com/neox5/otelbox/internal/cron"
 )
 
 // resolveTemplateClocks resolves clock templates (no dependencies)
@@ -18,14 +20,14 @@ func (r *Resolver) resolveTemplateClocks() error {
 		resolved := ClockConfig{
 			Type:     getStringValue(raw.Type),
 			Interval: raw.Interval,
+			Jitter:   raw.Jitter,
+			Rate:     raw.Rate,
+			Schedule: raw.Schedule,
+			Timezone: raw.Timezone,
 		}
 
-		// Validate
-		if resolved.Type == "" {
-			return ctx.error("type required")
-		}
-		if resolved.Interval == 0 {
-			return ctx.error("interval required")
+		if err := validateClock(resolved, ctx); err != nil {
+			return err
 		}
 
 		r.templateClocks[name] = resolved
@@ -48,14 +50,14 @@ func (r *Resolver) resolveInstanceClocks() error {
 		resolved := ClockConfig{
 			Type:     getStringValue(raw.Type),
 			Interval: raw.Interval,
+			Jitter:   raw.Jitter,
+			Rate:     raw.Rate,
+			Schedule: raw.Schedule,
+			Timezone: raw.Timezone,
 		}
 
-		// Validate
-		if resolved.Type == "" {
-			return ctx.error("type required")
-		}
-		if resolved.Interval == 0 {
-			return ctx.error("interval required")
+		if err := validateClock(resolved, ctx); err != nil {
+			return err
 		}
 
 		r.instanceClocks[name] = resolved
@@ -74,7 +76,8 @@ func (r *Resolver) resolveClockReference(raw *RawClockReference, ctx resolveCont
 			return ClockConfig{}, nil, ctx.error(fmt.Sprintf("clock instance %q not found", raw.Instance))
 		}
 		// No overrides allowed for instances
-		if raw.Template != "" || raw.Type != nil || raw.Interval != 0 {
+		if raw.Template != "" || raw.Type != nil || raw.Interval != 0 || raw.Jitter != 0 || raw.Rate != 0 ||
+			raw.Schedule != "" || raw.Timezone != "" {
 			return ClockConfig{}, nil, ctx.error("cannot override instance clock")
 		}
 		return instance, &raw.Instance, nil
@@ -95,6 +98,21 @@ func (r *Resolver) resolveClockReference(raw *RawClockReference, ctx resolveCont
 		if raw.Interval != 0 {
 			result.Interval = raw.Interval
 		}
+		if raw.Jitter != 0 {
+			result.Jitter = raw.Jitter
+		}
+		if raw.Rate != 0 {
+			result.Rate = raw.Rate
+		}
+		if raw.Schedule != "" {
+			result.Schedule = raw.Schedule
+		}
+		if raw.Timezone != "" {
+			result.Timezone = raw.Timezone
+		}
+		if err := validateClock(result, ctx); err != nil {
+			return ClockConfig{}, nil, err
+		}
 		return result, nil, nil
 	}
 
@@ -103,14 +121,18 @@ func (r *Resolver) resolveClockReference(raw *RawClockReference, ctx resolveCont
 		resolved := ClockConfig{
 			Type:     *raw.Type,
 			Interval: raw.Interval,
+			Jitter:   raw.Jitter,
+			Rate:     raw.Rate,
+			Schedule: raw.Schedule,
+			Timezone: raw.Timezone,
 		}
 
 		// Validate
 		if resolved.Type == "" {
 			return ClockConfig{}, nil, ctx.error("clock type required")
 		}
-		if resolved.Interval == 0 {
-			return ClockConfig{}, nil, ctx.error("clock interval required")
+		if err := validateClock(resolved, ctx); err != nil {
+			return ClockConfig{}, nil, err
 		}
 
 		return resolved, nil, nil
@@ -118,3 +140,63 @@ func (r *Resolver) resolveClockReference(raw *RawClockReference, ctx resolveCont
 
 	return ClockConfig{}, nil, ctx.error("clock must reference instance, template, or provide inline definition")
 }
+
+// validateClock validates type-specific parameters of a resolved clock config
+func validateClock(clk ClockConfig, ctx resolveContext) error {
+	switch clk.Type {
+	case "":
+		return ctx.error("type required")
+
+	case "periodic":
+		if clk.Interval <= 0 {
+			return ctx.error("interval required for periodic clock")
+		}
+
+	case "jitter":
+		if clk.Interval <= 0 {
+			return ctx.error("interval required for jitter clock")
+		}
+		if clk.Jitter <= 0 {
+			return ctx.error("jitter required for jitter clock")
+		}
+		if clk.Jitter >= clk.Interval {
+			return ctx.error(fmt.Sprintf("jitter (%s) must be less than interval (%s)", clk.Jitter, clk.Interval))
+		}
+
+	case "poisson":
+		if clk.Rate <= 0 {
+			return ctx.error("rate must be positive for poisson clock")
+		}
+		if clk.Interval != 0 {
+			return ctx.error("interval not supported for poisson clock, use rate")
+		}
+
+	case "cron":
+		if clk.Schedule == "" {
+			return ctx.error("schedule required for cron clock")
+		}
+		if _, err := cron.Parse(clk.Schedule); err != nil {
+			return ctx.error(fmt.Sprintf("invalid schedule %q: %v", clk.Schedule, err))
+		}
+		if _, err := loadLocation(clk.Timezone); err != nil {
+			return ctx.error(fmt.Sprintf("invalid timezone %q: %v", clk.Timezone, err))
+		}
+		if clk.Interval != 0 {
+			return ctx.error("interval not supported for cron clock, use schedule")
+		}
+
+	default:
+		return ctx.error(fmt.Sprintf("invalid clock type: %s (must be periodic, jitter, poisson or cron)", clk.Type))
+	}
+
+	if clk.Jitter != 0 && clk.Type != "jitter" {
+		return ctx.error(fmt.Sprintf("jitter not supported for %s clock", clk.Type))
+	}
+	if clk.Rate != 0 && clk.Type != "poisson" {
+		return ctx.error(fmt.Sprintf("rate not supported for %s clock", clk.Type))
+	}
+	if (clk.Schedule != "" || clk.Timezone != "") && clk.Type != "cron" {
+		return ctx.error(fmt.Sprintf("schedule and timezone not supported for %s clock", clk.Type))
+	}
+	return nil
+}
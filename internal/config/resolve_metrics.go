@@ -4,14 +4,135 @@ import (
 	"fmt"
 	"log/slog"
 	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
 )
 
-// resolveTemplateMetrics resolves metric templates (may reference value templates)
+// resolveTemplateMetrics resolves metric templates (may reference value templates
+// and earlier metric templates). Templates stay raw so metrics can override any
+// field, they are resolved and validated as part of each metric.
 func (r *Resolver) resolveTemplateMetrics() error {
-	// Template metrics not currently used - placeholder for future enhancement
+	for _, raw := range r.raw.Templates.Metrics {
+		name := raw.Name.Simple
+		if name == "" {
+			return fmt.Errorf("metric template name required (simple form)")
+		}
+		if err := r.registerName(name, "template metric"); err != nil {
+			return err
+		}
+
+		ctx := resolveContext{}.push("metric template", name)
+
+		resolved, err := r.inheritMetricTemplate(raw, ctx)
+		if err != nil {
+			return err
+		}
+
+		// Check the value early, unused templates would hide errors otherwise
+		if !resolved.Value.isEmpty() {
+			if _, err := r.resolveValue(&resolved.Value, ctx); err != nil {
+				return err
+			}
+		}
+
+		r.templateMetrics[name] = resolved
+
+		slog.Debug("template metric", "name", name, "type", resolved.Type)
+	}
 	return nil
 }
 
+// inheritMetricTemplate merges raw over the metric template it references.
+// Without a template reference raw is returned unchanged.
+func (r *Resolver) inheritMetricTemplate(raw RawMetricConfig, ctx resolveContext) (RawMetricConfig, error) {
+	if raw.AttributesMode != "" && raw.AttributesMode != AttributesModeMerge && raw.AttributesMode != AttributesModeReplace {
+		return RawMetricConfig{}, ctx.error(fmt.Sprintf("invalid attributes_mode: %s (must be merge or replace)", raw.AttributesMode))
+	}
+
+	if raw.Template == "" {
+		if raw.AttributesMode != "" {
+			return RawMetricConfig{}, ctx.error("attributes_mode requires template")
+		}
+		return raw.DeepCopy(), nil
+	}
+
+	template, exists := r.templateMetrics[raw.Template]
+	if !exists {
+		return RawMetricConfig{}, ctx.error(fmt.Sprintf("metric template %q not found", raw.Template))
+	}
+
+	return mergeMetric(template, raw), nil
+}
+
+// mergeMetric applies the fields set in override to a copy of base.
+//
+// Scalars replace inherited ones when set. A value with its own instance,
+// template, source, derive or expr replaces the inherited value, otherwise its
+// transforms, reset and restart override those of the inherited value.
+// Histogram, summary and stateset settings replace the inherited section.
+// Attributes are merged with override taking precedence, unless
+// attributes_mode is replace.
+func mergeMetric(base, override RawMetricConfig) RawMetricConfig {
+	result := base.DeepCopy()
+	override = override.DeepCopy()
+
+	result.Name = override.Name
+	result.Template = ""
+	result.AttributesMode = ""
+
+	if override.Type != "" {
+		result.Type = override.Type
+	}
+	if override.ValueType != "" {
+		result.ValueType = override.ValueType
+	}
+	if override.Description != "" {
+		result.Description = override.Description
+	}
+	if override.Read != "" {
+		result.Read = override.Read
+	}
+
+	value := override.Value
+	if value.Instance != "" || value.Template != "" || value.Source != nil || value.Derive != nil || value.Expr != "" {
+		result.Value = value
+	} else {
+		if len(value.Transforms) > 0 {
+			result.Value.Transforms = value.Transforms
+		}
+		if value.Reset.Type != "" {
+			result.Value.Reset = value.Reset
+		}
+		if value.Restart != nil {
+			result.Value.Restart = value.Restart
+		}
+	}
+
+	if override.Histogram != nil {
+		result.Histogram = override.Histogram
+	}
+	if override.Summary != nil {
+		result.Summary = override.Summary
+	}
+	if override.StateSet != nil {
+		result.StateSet = override.StateSet
+	}
+
+	if override.AttributesMode == AttributesModeReplace {
+		result.Attributes = override.Attributes
+	} else if override.Attributes != nil {
+		if result.Attributes == nil {
+			result.Attributes = make(map[string]string, len(override.Attributes))
+		}
+		maps.Copy(result.Attributes, override.Attributes)
+	}
+
+	return result
+}
+
 // resolveMetrics resolves final metrics from raw config
 func (r *Resolver) resolveMetrics() ([]MetricConfig, error) {
 	var metrics []MetricConfig
@@ -20,7 +141,15 @@ func (r *Resolver) resolveMetrics() ([]MetricConfig, error) {
 		promName := raw.Name.GetPrometheusName()
 		ctx := resolveContext{}.push("metric", promName)
 
-		metric, err := r.resolveMetric(&raw, ctx)
+		merged, err := r.inheritMetricTemplate(raw, ctx)
+		if err != nil {
+			return nil, err
+		}
+		if raw.Template != "" {
+			ctx = ctx.push("metric template", raw.Template)
+		}
+
+		metric, err := r.resolveMetric(&merged, ctx)
 		if err != nil {
 			return nil, err
 		}
@@ -38,17 +167,81 @@ func (r *Resolver) resolveMetric(raw *RawMetricConfig, ctx resolveContext) (Metr
 		PrometheusName: raw.Name.GetPrometheusName(),
 		OTELName:       raw.Name.GetOTELName(),
 		Type:           MetricType(raw.Type),
+		ValueType:      ValueType(raw.ValueType),
 		Description:    raw.Description,
 	}
 
-	// Always resolve to full ValueConfig
-	value, err := r.resolveValue(&raw.Value, ctx)
-	if err != nil {
-		return MetricConfig{}, err
+	// Apply value type default - histogram and summary observations are always float
+	if result.ValueType == "" {
+		result.ValueType = ValueTypeInt
+		if result.Type == MetricTypeHistogram || result.Type == MetricTypeSummary {
+			result.ValueType = ValueTypeFloat
+		}
+	}
+
+	// Histogram settings only apply to histogram metrics
+	if raw.Histogram != nil {
+		if result.Type != MetricTypeHistogram {
+			return MetricConfig{}, ctx.error("histogram settings require type histogram")
+		}
+		if err := resolveHistogram(raw.Histogram, &result.Histogram, ctx); err != nil {
+			return MetricConfig{}, err
+		}
+	}
+
+	// Summary settings only apply to summary metrics
+	if raw.Summary != nil && result.Type != MetricTypeSummary {
+		return MetricConfig{}, ctx.error("summary settings require type summary")
+	}
+	if result.Type == MetricTypeSummary {
+		result.Summary = SummaryConfig{
+			Quantiles: slices.Clone(DefaultSummaryQuantiles),
+			Window:    DefaultSummaryWindow,
+		}
+		if raw.Summary != nil && raw.Summary.Quantiles != nil {
+			result.Summary.Quantiles = slices.Clone(raw.Summary.Quantiles)
+		}
+		if raw.Summary != nil && raw.Summary.Window != nil {
+			result.Summary.Window = *raw.Summary.Window
+		}
+	}
+
+	// Stateset settings only apply to stateset metrics
+	if raw.StateSet != nil && result.Type != MetricTypeStateSet {
+		return MetricConfig{}, ctx.error("stateset settings require type stateset")
+	}
+	if result.Type == MetricTypeStateSet && raw.StateSet != nil {
+		result.StateSet = resolveStateSet(raw.StateSet)
+	}
+
+	// Info metrics have a constant value and no value reference
+	if result.Type == MetricTypeInfo {
+		if !raw.Value.isEmpty() {
+			return MetricConfig{}, ctx.error("value not supported for info")
+		}
+	} else {
+		// Always resolve to full ValueConfig
+		value, err := r.resolveValue(&raw.Value, ctx)
+		if err != nil {
+			return MetricConfig{}, err
+		}
+		result.Value = value
+	}
+
+	// Read mode applies to metrics exporting the current value
+	switch result.Type {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeUpDownCounter:
+		result.Read = ReadMode(raw.Read)
+		if result.Read == "" {
+			result.Read = ReadModeConsume
+		}
+	default:
+		if raw.Read != "" {
+			return MetricConfig{}, ctx.error("read requires type counter, gauge or updowncounter")
+		}
 	}
-	result.Value = value
 
-	// Apply attribute overrides (complete replacement if specified)
+	// Attributes are already merged with the template
 	if raw.Attributes != nil {
 		result.Attributes = make(map[string]string, len(raw.Attributes))
 		maps.Copy(result.Attributes, raw.Attributes)
@@ -72,8 +265,35 @@ func (r *Resolver) validateMetric(metric MetricConfig, ctx resolveContext) error
 	}
 
 	// Validate type is valid
-	if metric.Type != MetricTypeCounter && metric.Type != MetricTypeGauge {
-		return ctx.error(fmt.Sprintf("invalid type: %s (must be counter or gauge)", metric.Type))
+	switch metric.Type {
+	case MetricTypeCounter, MetricTypeGauge, MetricTypeUpDownCounter:
+	case MetricTypeHistogram:
+		if err := validateHistogram(metric, ctx); err != nil {
+			return err
+		}
+	case MetricTypeSummary:
+		if err := validateSummary(metric, ctx); err != nil {
+			return err
+		}
+	case MetricTypeInfo:
+		if metric.ValueType != ValueTypeInt {
+			return ctx.error("value_type must be int for info")
+		}
+		// Prometheus sees a gauge, the suffix identifies info metrics
+		if !strings.HasSuffix(metric.PrometheusName, "_info") {
+			return ctx.error(fmt.Sprintf("prometheus name of info metric must end with _info: %s", metric.PrometheusName))
+		}
+	case MetricTypeStateSet:
+		if err := validateStateSet(metric, ctx); err != nil {
+			return err
+		}
+	default:
+		return ctx.error(fmt.Sprintf("invalid type: %s (must be counter, gauge, updowncounter, histogram, summary, info or stateset)", metric.Type))
+	}
+
+	// Validate value type
+	if metric.ValueType != ValueTypeInt && metric.ValueType != ValueTypeFloat {
+		return ctx.error(fmt.Sprintf("invalid value_type: %s (must be int or float)", metric.ValueType))
 	}
 
 	// Description required
@@ -81,11 +301,223 @@ func (r *Resolver) validateMetric(metric MetricConfig, ctx resolveContext) error
 		return ctx.error("description required")
 	}
 
-	// Value must be populated
-	if metric.Value.Source.Type == "" {
+	// Value must be populated (info metrics are constant)
+	if metric.Type != MetricTypeInfo && metric.Value.Source.Type == "" && metric.Value.Derive == nil && metric.Value.Expr == nil {
 		return ctx.error("value source required")
 	}
 
+	// Validate read mode
+	if metric.Read != "" && metric.Read != ReadModeConsume && metric.Read != ReadModePeek {
+		return ctx.error(fmt.Sprintf("invalid read: %s (must be consume or peek)", metric.Read))
+	}
+
+	// Template transforms, reset and restart may have been overridden
+	if err := validateTransforms(metric.Value.Transforms, ctx); err != nil {
+		return err
+	}
+	if err := validateRestart(metric.Value, ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// resolveHistogram copies raw histogram settings and applies mode defaults
+func resolveHistogram(raw *RawHistogramConfig, dst *HistogramConfig, ctx resolveContext) error {
+	dst.Mode = HistogramMode(raw.Mode)
+	dst.Buckets = slices.Clone(raw.Buckets)
+
+	if dst.Mode == "" {
+		dst.Mode = HistogramModeExplicit
+	}
+
+	switch dst.Mode {
+	case HistogramModeExplicit:
+		if raw.Scale != nil || raw.MaxBuckets != nil {
+			return ctx.error("scale and max_buckets require histogram mode exponential")
+		}
+	case HistogramModeExponential:
+		dst.Scale = DefaultHistogramScale
+		if raw.Scale != nil {
+			dst.Scale = *raw.Scale
+		}
+		dst.MaxBuckets = DefaultHistogramMaxBuckets
+		if raw.MaxBuckets != nil {
+			dst.MaxBuckets = *raw.MaxBuckets
+		}
+	default:
+		return ctx.error(fmt.Sprintf("invalid histogram mode: %s (must be explicit or exponential)", dst.Mode))
+	}
+
+	return nil
+}
+
+// validateObservedValue validates the value of metrics aggregating value updates
+func validateObservedValue(metric MetricConfig, ctx resolveContext) error {
+	if metric.ValueType != ValueTypeFloat {
+		return ctx.error(fmt.Sprintf("value_type must be float for %s", metric.Type))
+	}
+
+	// Every value update is an observation, reset would only affect the unused value state
+	if metric.Value.Reset.Type != "" {
+		return ctx.error(fmt.Sprintf("reset not supported for %s", metric.Type))
+	}
+	if metric.Value.Restart != nil {
+		return ctx.error(fmt.Sprintf("restart not supported for %s", metric.Type))
+	}
+
+	return nil
+}
+
+// validateSummary validates summary specific settings
+func validateSummary(metric MetricConfig, ctx resolveContext) error {
+	if err := validateObservedValue(metric, ctx); err != nil {
+		return err
+	}
+
+	quantiles := metric.Summary.Quantiles
+	if len(quantiles) == 0 {
+		return ctx.error("summary quantiles required")
+	}
+	for i, q := range quantiles {
+		if math.IsNaN(q) || q < 0 || q > 1 {
+			return ctx.error(fmt.Sprintf("summary quantile %g out of range (0 to 1)", q))
+		}
+		if i > 0 && q <= quantiles[i-1] {
+			return ctx.error(fmt.Sprintf("summary quantiles must be strictly increasing (%g after %g)", q, quantiles[i-1]))
+		}
+	}
+
+	if metric.Summary.Window < 1 {
+		return ctx.error("summary window must be at least 1")
+	}
+
+	return nil
+}
+
+// resolveStateSet copies raw stateset settings and applies defaults
+func resolveStateSet(raw *RawStateSetConfig) StateSetConfig {
+	result := StateSetConfig{
+		States:  slices.Clone(raw.States),
+		Label:   raw.Label,
+		Initial: raw.Initial,
+	}
+
+	if raw.Transitions != nil {
+		result.Transitions = make(map[string]map[string]float64, len(raw.Transitions))
+		for from, weights := range raw.Transitions {
+			result.Transitions[from] = maps.Clone(weights)
+		}
+	}
+
+	if result.Label == "" {
+		result.Label = DefaultStateSetLabel
+	}
+	if result.Initial == "" && len(result.States) > 0 {
+		result.Initial = result.States[0]
+	}
+
+	return result
+}
+
+// validateStateSet validates stateset specific settings
+func validateStateSet(metric MetricConfig, ctx resolveContext) error {
+	if metric.ValueType != ValueTypeInt {
+		return ctx.error("value_type must be int for stateset")
+	}
+
+	// Value updates select or advance the state, the value itself is never read
+	if metric.Value.Reset.Type != "" {
+		return ctx.error("reset not supported for stateset")
+	}
+	if metric.Value.Restart != nil {
+		return ctx.error("restart not supported for stateset")
+	}
+
+	stateSet := metric.StateSet
+	if len(stateSet.States) == 0 {
+		return ctx.error("stateset states required")
+	}
+
+	known := make(map[string]bool, len(stateSet.States))
+	for _, state := range stateSet.States {
+		if state == "" {
+			return ctx.error("stateset state cannot be empty")
+		}
+		if known[state] {
+			return ctx.error(fmt.Sprintf("duplicate stateset state %q", state))
+		}
+		known[state] = true
+	}
+
+	if !IsValidAttributeName(stateSet.Label) {
+		return ctx.error(fmt.Sprintf("invalid stateset label %q", stateSet.Label))
+	}
+	if _, exists := metric.Attributes[stateSet.Label]; exists {
+		return ctx.error(fmt.Sprintf("stateset label %q conflicts with attribute", stateSet.Label))
+	}
+
+	if !known[stateSet.Initial] {
+		return ctx.error(fmt.Sprintf("unknown initial state %q", stateSet.Initial))
+	}
+
+	for from, weights := range stateSet.Transitions {
+		if !known[from] {
+			return ctx.error(fmt.Sprintf("unknown state %q in transitions", from))
+		}
+
+		var total float64
+		for to, weight := range weights {
+			if !known[to] {
+				return ctx.error(fmt.Sprintf("unknown state %q in transitions from %q", to, from))
+			}
+			if weight < 0 {
+				return ctx.error(fmt.Sprintf("negative transition weight from %q to %q", from, to))
+			}
+			total += weight
+		}
+		if total == 0 {
+			return ctx.error(fmt.Sprintf("transitions from %q need a positive weight", from))
+		}
+	}
+
+	return nil
+}
+
+// validateHistogram validates histogram specific settings
+func validateHistogram(metric MetricConfig, ctx resolveContext) error {
+	if err := validateObservedValue(metric, ctx); err != nil {
+		return err
+	}
+
+	histogram := metric.Histogram
+	if histogram.Mode == HistogramModeExponential {
+		if len(histogram.Buckets) > 0 {
+			return ctx.error("buckets not supported for exponential histogram")
+		}
+		if histogram.Scale < MinHistogramScale || histogram.Scale > MaxHistogramScale {
+			return ctx.error(fmt.Sprintf("histogram scale %d out of range (%d to %d)",
+				histogram.Scale, MinHistogramScale, MaxHistogramScale))
+		}
+		if histogram.MaxBuckets < 1 {
+			return ctx.error("histogram max_buckets must be at least 1")
+		}
+		return nil
+	}
+
+	buckets := histogram.Buckets
+	if len(buckets) == 0 {
+		return ctx.error("histogram buckets required")
+	}
+	for i, bound := range buckets {
+		if math.IsNaN(bound) || math.IsInf(bound, 0) {
+			return ctx.error(fmt.Sprintf("histogram bucket %g must be finite", bound))
+		}
+		if i > 0 && bound <= buckets[i-1] {
+			return ctx.error(fmt.Sprintf("histogram buckets must be strictly increasing (%g after %g)", bound, buckets[i-1]))
+		}
+	}
+
 	return nil
 }
 
@@ -116,6 +548,17 @@ func resolveExport(raw *RawExportConfig) (ExportConfig, error) {
 			Resource: copyStringMap(raw.OTEL.Resource),
 			Headers:  copyStringMap(raw.OTEL.Headers),
 		}
+
+		if raw.OTEL.File != nil {
+			result.OTEL.File = &OTELFileConfig{Path: raw.OTEL.File.Path}
+			if rotation := raw.OTEL.File.Rotation; rotation != nil {
+				result.OTEL.File.Rotation = RotationConfig{
+					MaxMegabytes: rotation.MaxMegabytes,
+					Interval:     rotation.Interval,
+					MaxBackups:   rotation.MaxBackups,
+				}
+			}
+		}
 	}
 
 	// Validate converted config
@@ -136,6 +579,12 @@ func resolveSettings(raw *RawSettingsConfig) (SettingsConfig, error) {
 		},
 	}
 
+	timeCfg, err := resolveTime(&raw.Time, time.Now())
+	if err != nil {
+		return SettingsConfig{}, err
+	}
+	result.Time = timeCfg
+
 	// Validate converted config
 	if err := result.Validate(); err != nil {
 		return SettingsConfig{}, err
@@ -144,6 +593,58 @@ func resolveSettings(raw *RawSettingsConfig) (SettingsConfig, error) {
 	return result, nil
 }
 
+// resolveTime converts raw time config to resolved time config.
+// Offsets in start and end are relative to now. Virtual time starts now
+// if only speed or end is set.
+func resolveTime(raw *RawTimeConfig, now time.Time) (TimeConfig, error) {
+	if *raw == (RawTimeConfig{}) {
+		return TimeConfig{}, nil
+	}
+
+	result := TimeConfig{Speed: 1, Start: now}
+
+	switch raw.Speed {
+	case "":
+	case "max":
+		result.Speed = 0
+	default:
+		speed, err := strconv.ParseFloat(raw.Speed, 64)
+		if err != nil || speed <= 0 || math.IsInf(speed, 0) || math.IsNaN(speed) {
+			return TimeConfig{}, fmt.Errorf("invalid time speed: %q (must be positive number or max)", raw.Speed)
+		}
+		result.Speed = speed
+	}
+
+	if raw.Start != "" {
+		start, err := ParseTimePoint(raw.Start, now)
+		if err != nil {
+			return TimeConfig{}, fmt.Errorf("invalid time start: %w", err)
+		}
+		result.Start = start
+	}
+
+	if raw.End != "" {
+		end, err := ParseTimePoint(raw.End, now)
+		if err != nil {
+			return TimeConfig{}, fmt.Errorf("invalid time end: %w", err)
+		}
+		result.End = end
+	}
+
+	return result, nil
+}
+
+// ParseTimePoint parses an RFC 3339 timestamp or a duration offset from now.
+func ParseTimePoint(text string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, text); err == nil {
+		return t, nil
+	}
+	if offset, err := time.ParseDuration(text); err == nil {
+		return now.Add(offset), nil
+	}
+	return time.Time{}, fmt.Errorf("%q (must be RFC 3339 timestamp or offset like -24h)", text)
+}
+
 // copyStringMap creates a copy of a string map (handles nil)
 func copyStringMap(src map[string]string) map[string]string {
 	if src == nil {
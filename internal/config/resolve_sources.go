@@ -3,6 +3,10 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // resolveTemplateSources resolves source templates (may reference clock templates)
@@ -30,16 +34,11 @@ func (r *Resolver) resolveTemplateSources() error {
 		}
 
 		// Copy optional fields
-		if raw.Min != nil {
-			resolved.Min = *raw.Min
-		}
-		if raw.Max != nil {
-			resolved.Max = *raw.Max
-		}
+		raw.applyParameters(&resolved)
 
 		// Validate
-		if resolved.Type == "" {
-			return ctx.error("type required")
+		if err := r.validateSource(&resolved, ctx); err != nil {
+			return err
 		}
 
 		r.templateSources[name] = resolved
@@ -74,16 +73,11 @@ func (r *Resolver) resolveInstanceSources() error {
 		}
 
 		// Copy optional fields
-		if raw.Min != nil {
-			resolved.Min = *raw.Min
-		}
-		if raw.Max != nil {
-			resolved.Max = *raw.Max
-		}
+		raw.applyParameters(&resolved)
 
 		// Validate
-		if resolved.Type == "" {
-			return ctx.error("type required")
+		if err := r.validateSource(&resolved, ctx); err != nil {
+			return err
 		}
 
 		r.instanceSources[name] = resolved
@@ -102,7 +96,7 @@ func (r *Resolver) resolveSourceReference(raw *RawSourceReference, ctx resolveCo
 			return SourceConfig{}, nil, ctx.error(fmt.Sprintf("source instance %q not found", raw.Instance))
 		}
 		// No overrides allowed for instances
-		if raw.Template != "" || raw.Type != nil || raw.Clock != nil || raw.Min != nil || raw.Max != nil {
+		if raw.Template != "" || raw.Type != nil || raw.Clock != nil || raw.hasParameters() {
 			return SourceConfig{}, nil, ctx.error("cannot override instance source")
 		}
 		return instance, &raw.Instance, nil // Return instance ref
@@ -128,11 +122,11 @@ func (r *Resolver) resolveSourceReference(raw *RawSourceReference, ctx resolveCo
 			result.Clock = clock
 			result.ClockRef = clockRef
 		}
-		if raw.Min != nil {
-			result.Min = *raw.Min
-		}
-		if raw.Max != nil {
-			result.Max = *raw.Max
+		raw.applyParameters(&result)
+
+		// Validate
+		if err := r.validateSource(&result, ctx); err != nil {
+			return SourceConfig{}, nil, err
 		}
 		return result, nil, nil // No instance ref for templates
 	}
@@ -153,20 +147,187 @@ func (r *Resolver) resolveSourceReference(raw *RawSourceReference, ctx resolveCo
 		}
 
 		// Copy optional fields
-		if raw.Min != nil {
-			result.Min = *raw.Min
-		}
-		if raw.Max != nil {
-			result.Max = *raw.Max
-		}
+		raw.applyParameters(&result)
 
 		// Validate
 		if result.Type == "" {
 			return SourceConfig{}, nil, ctx.error("source type required")
 		}
+		if err := r.validateSource(&result, ctx); err != nil {
+			return SourceConfig{}, nil, err
+		}
 
 		return result, nil, nil
 	}
 
 	return SourceConfig{}, nil, ctx.error("source must reference instance, template, or provide inline definition")
 }
+
+// validateSource applies defaults and validates type-specific parameters of a resolved source config
+func (r *Resolver) validateSource(source *SourceConfig, ctx resolveContext) error {
+	switch source.Type {
+	case "":
+		return ctx.error("type required")
+
+	case "random_int":
+		if source.Min != math.Trunc(source.Min) || source.Max != math.Trunc(source.Max) {
+			return ctx.error("min and max must be integers for random_int source")
+		}
+		if source.Min > source.Max {
+			return ctx.error(fmt.Sprintf("min (%g) must not exceed max (%g)", source.Min, source.Max))
+		}
+
+	case "random_float":
+		if source.Min >= source.Max {
+			return ctx.error(fmt.Sprintf("min (%g) must be less than max (%g) for random_float source", source.Min, source.Max))
+		}
+
+	case "sine", "cosine":
+		if source.Period <= 0 {
+			return ctx.error(fmt.Sprintf("period required for %s source", source.Type))
+		}
+		if source.Amplitude == 0 {
+			return ctx.error(fmt.Sprintf("amplitude required for %s source", source.Type))
+		}
+
+	case "random_walk":
+		// Apply defaults
+		if source.StepDistribution == "" {
+			source.StepDistribution = StepDistributionUniform
+		}
+		if source.Boundary == "" {
+			source.Boundary = BoundaryClamp
+		}
+
+		if source.Min >= source.Max {
+			return ctx.error(fmt.Sprintf("min (%g) must be less than max (%g) for random_walk source", source.Min, source.Max))
+		}
+		if source.Start < source.Min || source.Start > source.Max {
+			return ctx.error(fmt.Sprintf("start (%g) must be within [%g, %g]", source.Start, source.Min, source.Max))
+		}
+		if source.Step <= 0 {
+			return ctx.error("step must be positive for random_walk source")
+		}
+		switch source.StepDistribution {
+		case StepDistributionUniform, StepDistributionNormal:
+		default:
+			return ctx.error(fmt.Sprintf("invalid step_distribution: %s (must be uniform or normal)", source.StepDistribution))
+		}
+		switch source.Boundary {
+		case BoundaryClamp, BoundaryReflect:
+		default:
+			return ctx.error(fmt.Sprintf("invalid boundary: %s (must be clamp or reflect)", source.Boundary))
+		}
+
+	case "normal":
+		if source.Stddev <= 0 {
+			return ctx.error("stddev must be positive for normal source")
+		}
+
+	case "exponential", "poisson":
+		if source.Lambda <= 0 {
+			return ctx.error(fmt.Sprintf("lambda must be positive for %s source", source.Type))
+		}
+
+	case "lognormal":
+		if source.Sigma <= 0 {
+			return ctx.error("sigma must be positive for lognormal source")
+		}
+
+	case "replay":
+		if source.File == "" {
+			return ctx.error("file required for replay source")
+		}
+		if !filepath.IsAbs(source.File) {
+			source.File = filepath.Join(r.raw.Dir, source.File)
+		}
+
+		// Infer format from file extension
+		if source.Format == "" {
+			switch strings.ToLower(filepath.Ext(source.File)) {
+			case ".csv":
+				source.Format = ReplayFormatCSV
+			case ".ndjson", ".jsonl":
+				source.Format = ReplayFormatNDJSON
+			default:
+				return ctx.error(fmt.Sprintf("cannot infer format of %q, set format to csv or ndjson", source.File))
+			}
+		}
+		if source.Format != ReplayFormatCSV && source.Format != ReplayFormatNDJSON {
+			return ctx.error(fmt.Sprintf("invalid format: %s (must be csv or ndjson)", source.Format))
+		}
+
+		// Read the recording now, so broken files fail with the source context
+		samples, err := loadReplaySamples(source.File, source.Format)
+		if err != nil {
+			return ctx.error(err.Error())
+		}
+		source.Samples = samples
+
+	default:
+		return ctx.error(fmt.Sprintf("unknown source type: %s", source.Type))
+	}
+
+	// Parameters of other source types are not allowed
+	parameters := []struct {
+		names string
+		set   bool
+		types []string
+	}{
+		{"min and max", source.Min != 0 || source.Max != 0,
+			[]string{"random_int", "random_float", "random_walk"}},
+		{"amplitude, offset, period and phase", source.Amplitude != 0 || source.Offset != 0 || source.Period != 0 || source.Phase != 0,
+			[]string{"sine", "cosine"}},
+		{"start, step, step_distribution, drift and boundary", source.Start != 0 || source.Step != 0 || source.StepDistribution != "" || source.Drift != 0 || source.Boundary != "",
+			[]string{"random_walk"}},
+		{"mean and stddev", source.Mean != 0 || source.Stddev != 0,
+			[]string{"normal"}},
+		{"lambda", source.Lambda != 0,
+			[]string{"exponential", "poisson"}},
+		{"mu and sigma", source.Mu != 0 || source.Sigma != 0,
+			[]string{"lognormal"}},
+		{"file, format and loop", source.File != "" || source.Format != "" || source.Loop,
+			[]string{"replay"}},
+	}
+	for _, p := range parameters {
+		if p.set && !slices.Contains(p.types, source.Type) {
+			return ctx.error(fmt.Sprintf("%s not supported for %s source", p.names, source.Type))
+		}
+	}
+
+	if source.Profile != nil {
+		return validateProfile(source.Profile, ctx)
+	}
+	return nil
+}
+
+// validateProfile applies defaults and validates a load profile
+func validateProfile(profile *ProfileConfig, ctx resolveContext) error {
+	if profile.Interpolation == "" {
+		profile.Interpolation = ProfileInterpolationStep
+	}
+
+	if profile.Hours == nil && profile.Days == nil {
+		return ctx.error("profile requires hours or days")
+	}
+	if profile.Hours != nil && len(profile.Hours) != 24 {
+		return ctx.error(fmt.Sprintf("profile hours requires 24 factors, got %d", len(profile.Hours)))
+	}
+	if profile.Days != nil && len(profile.Days) != 7 {
+		return ctx.error(fmt.Sprintf("profile days requires 7 factors, got %d", len(profile.Days)))
+	}
+	for _, factor := range slices.Concat(profile.Hours, profile.Days) {
+		if factor < 0 {
+			return ctx.error(fmt.Sprintf("profile factor %g must not be negative", factor))
+		}
+	}
+	switch profile.Interpolation {
+	case ProfileInterpolationStep, ProfileInterpolationLinear:
+	default:
+		return ctx.error(fmt.Sprintf("invalid profile interpolation: %s (must be step or linear)", profile.Interpolation))
+	}
+	if _, err := loadLocation(profile.Timezone); err != nil {
+		return ctx.error(fmt.Sprintf("invalid profile timezone %q: %v", profile.Timezone, err))
+	}
+	return nil
+}
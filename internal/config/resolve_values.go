@@ -3,6 +3,10 @@ package config
 import (
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
+
+	"github.com/neox5/otelbox/internal/expr"
 )
 
 // resolveTemplateValues resolves value templates (may reference source templates)
@@ -17,19 +21,23 @@ func (r *Resolver) resolveTemplateValues() error {
 
 		resolved := ValueConfig{}
 
-		// Resolve source (inline only for templates)
-		if raw.Source != nil {
-			source, sourceRef, err := r.resolveSourceReference(raw.Source, ctx)
-			if err != nil {
-				return err
-			}
-			resolved.Source = source
-			resolved.SourceRef = sourceRef
+		// Resolve source (inline only for templates), derive or expr
+		if err := r.resolveValueInput(&raw, &resolved, ctx); err != nil {
+			return err
 		}
 
-		// Copy transforms and reset
+		// Copy transforms, resolve reset and restart
 		resolved.Transforms = raw.Transforms
-		resolved.Reset = raw.Reset
+		reset, err := r.resolveReset(&raw.Reset, ctx)
+		if err != nil {
+			return err
+		}
+		resolved.Reset = reset
+		restart, err := r.resolveRestart(raw.Restart, ctx)
+		if err != nil {
+			return err
+		}
+		resolved.Restart = restart
 
 		// Validate
 		if err := r.validateValue(resolved, ctx); err != nil {
@@ -55,19 +63,23 @@ func (r *Resolver) resolveInstanceValues() error {
 
 		resolved := ValueConfig{}
 
-		// Resolve source reference if present
-		if raw.Source != nil {
-			source, sourceRef, err := r.resolveSourceReference(raw.Source, ctx)
-			if err != nil {
-				return err
-			}
-			resolved.Source = source
-			resolved.SourceRef = sourceRef
+		// Resolve source reference, derive or expr if present
+		if err := r.resolveValueInput(&raw, &resolved, ctx); err != nil {
+			return err
 		}
 
-		// Copy transforms and reset
+		// Copy transforms, resolve reset and restart
 		resolved.Transforms = raw.Transforms
-		resolved.Reset = raw.Reset
+		reset, err := r.resolveReset(&raw.Reset, ctx)
+		if err != nil {
+			return err
+		}
+		resolved.Reset = reset
+		restart, err := r.resolveRestart(raw.Restart, ctx)
+		if err != nil {
+			return err
+		}
+		resolved.Restart = restart
 
 		// Validate
 		if err := r.validateValue(resolved, ctx); err != nil {
@@ -92,11 +104,15 @@ func (r *Resolver) resolveValue(raw *RawValueReference, ctx resolveContext) (Val
 		}
 
 		// No overrides allowed for instances
-		if raw.Template != "" || raw.Source != nil ||
-			len(raw.Transforms) > 0 || raw.Reset.Type != "" {
+		if raw.Template != "" || raw.Source != nil || raw.Derive != nil || raw.Expr != "" ||
+			len(raw.Transforms) > 0 || raw.Reset.Type != "" || raw.Restart != nil {
 			return ValueConfig{}, ctx.error("cannot override instance value")
 		}
 
+		// Metrics referencing the instance share one value
+		instanceName := raw.Instance
+		instance.ValueRef = &instanceName
+
 		return instance, nil // Returns full config with references preserved
 	}
 
@@ -110,13 +126,9 @@ func (r *Resolver) resolveValue(raw *RawValueReference, ctx resolveContext) (Val
 		// Start with template, apply overrides
 		result := template
 
-		if raw.Source != nil {
-			source, sourceRef, err := r.resolveSourceReference(raw.Source, ctx)
-			if err != nil {
-				return ValueConfig{}, err
-			}
-			result.Source = source
-			result.SourceRef = sourceRef // Preserve reference tracking
+		// Source, derive or expr replaces the one of the template
+		if err := r.resolveValueInput(raw, &result, ctx); err != nil {
+			return ValueConfig{}, err
 		}
 
 		if len(raw.Transforms) > 0 {
@@ -124,34 +136,206 @@ func (r *Resolver) resolveValue(raw *RawValueReference, ctx resolveContext) (Val
 		}
 
 		if raw.Reset.Type != "" {
-			result.Reset = raw.Reset
+			reset, err := r.resolveReset(&raw.Reset, ctx)
+			if err != nil {
+				return ValueConfig{}, err
+			}
+			result.Reset = reset
+		}
+
+		if raw.Restart != nil {
+			restart, err := r.resolveRestart(raw.Restart, ctx)
+			if err != nil {
+				return ValueConfig{}, err
+			}
+			result.Restart = restart
 		}
 
 		return result, nil
 	}
 
-	// Case 3: Inline definition - must have source
-	if raw.Source == nil {
-		return ValueConfig{}, ctx.error("value must reference instance, template, or provide inline source")
+	// Case 3: Inline definition - must have source, derive or expr
+	if raw.Source == nil && raw.Derive == nil && raw.Expr == "" {
+		return ValueConfig{}, ctx.error("value must reference instance, template, or provide inline source, derive or expr")
 	}
 
 	result := ValueConfig{}
 
-	source, sourceRef, err := r.resolveSourceReference(raw.Source, ctx)
-	if err != nil {
+	if err := r.resolveValueInput(raw, &result, ctx); err != nil {
 		return ValueConfig{}, err
 	}
-	result.Source = source
-	result.SourceRef = sourceRef // Preserve reference tracking
 
 	result.Transforms = raw.Transforms
-	result.Reset = raw.Reset
+
+	reset, err := r.resolveReset(&raw.Reset, ctx)
+	if err != nil {
+		return ValueConfig{}, err
+	}
+	result.Reset = reset
+
+	restart, err := r.resolveRestart(raw.Restart, ctx)
+	if err != nil {
+		return ValueConfig{}, err
+	}
+	result.Restart = restart
+
+	return result, nil
+}
+
+// resolveValueInput resolves the source, derive or expr of a raw value into dst.
+// At most one may be set, it replaces any input already present in dst.
+func (r *Resolver) resolveValueInput(raw *RawValueReference, dst *ValueConfig, ctx resolveContext) error {
+	inputs := 0
+	for _, set := range []bool{raw.Source != nil, raw.Derive != nil, raw.Expr != ""} {
+		if set {
+			inputs++
+		}
+	}
+	if inputs > 1 {
+		return ctx.error("source, derive and expr are mutually exclusive")
+	}
+
+	switch {
+	case raw.Source != nil:
+		source, sourceRef, err := r.resolveSourceReference(raw.Source, ctx)
+		if err != nil {
+			return err
+		}
+		*dst = ValueConfig{Source: source, SourceRef: sourceRef, Transforms: dst.Transforms, Reset: dst.Reset, Restart: dst.Restart}
+	case raw.Derive != nil:
+		derive, err := r.resolveDerive(raw.Derive, ctx)
+		if err != nil {
+			return err
+		}
+		*dst = ValueConfig{Derive: derive, Transforms: dst.Transforms, Reset: dst.Reset, Restart: dst.Restart}
+	case raw.Expr != "":
+		expression, err := r.resolveExpr(raw.Expr, ctx)
+		if err != nil {
+			return err
+		}
+		*dst = ValueConfig{Expr: expression, Transforms: dst.Transforms, Reset: dst.Reset, Restart: dst.Restart}
+	}
+
+	return nil
+}
+
+// resolveReset validates reset parameters and resolves the reset clock.
+// on_interval resets on a periodic clock of its own.
+func (r *Resolver) resolveReset(raw *RawResetConfig, ctx resolveContext) (ResetConfig, error) {
+	result := ResetConfig{
+		Type:  ResetType(raw.Type),
+		Value: raw.Value,
+	}
+
+	// Parameters of other reset types are not allowed
+	if raw.Interval != 0 && result.Type != ResetOnInterval {
+		return ResetConfig{}, ctx.error("reset interval requires type on_interval")
+	}
+	if raw.Clock != nil && result.Type != ResetOnTick {
+		return ResetConfig{}, ctx.error("reset clock requires type on_tick")
+	}
+	if raw.Threshold != nil && result.Type != ResetAtValue {
+		return ResetConfig{}, ctx.error("reset threshold requires type at_value")
+	}
+
+	switch result.Type {
+	case "":
+		if raw.Value != 0 {
+			return ResetConfig{}, ctx.error("reset type required")
+		}
+	case ResetOnRead:
+	case ResetOnInterval:
+		if raw.Interval <= 0 {
+			return ResetConfig{}, ctx.error("reset interval required for on_interval")
+		}
+		result.Clock = ClockConfig{Type: "periodic", Interval: raw.Interval}
+	case ResetOnTick:
+		if raw.Clock == nil {
+			return ResetConfig{}, ctx.error("reset clock required for on_tick")
+		}
+		clock, clockRef, err := r.resolveClockReference(raw.Clock, ctx)
+		if err != nil {
+			return ResetConfig{}, err
+		}
+		result.Clock = clock
+		result.ClockRef = clockRef
+	case ResetAtValue:
+		if raw.Threshold == nil {
+			return ResetConfig{}, ctx.error("reset threshold required for at_value")
+		}
+		if *raw.Threshold <= raw.Value {
+			return ResetConfig{}, ctx.error(fmt.Sprintf("reset threshold %g must be greater than reset value %g", *raw.Threshold, raw.Value))
+		}
+		result.Threshold = *raw.Threshold
+	default:
+		return ResetConfig{}, ctx.error(fmt.Sprintf("invalid reset type: %s (must be on_read, on_interval, on_tick or at_value)", result.Type))
+	}
 
 	return result, nil
 }
 
+// resolveRestart validates restart parameters and resolves the restart clock.
+// Returns nil if raw is nil.
+func (r *Resolver) resolveRestart(raw *RawRestartConfig, ctx resolveContext) (*RestartConfig, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	result := &RestartConfig{
+		Probability: 1,
+		Value:       raw.Value,
+	}
+
+	switch {
+	case raw.Interval != 0 && raw.Clock != nil:
+		return nil, ctx.error("restart interval and clock are mutually exclusive")
+	case raw.Interval > 0:
+		result.Clock = ClockConfig{Type: "periodic", Interval: raw.Interval}
+	case raw.Clock != nil:
+		clock, clockRef, err := r.resolveClockReference(raw.Clock, ctx)
+		if err != nil {
+			return nil, err
+		}
+		result.Clock = clock
+		result.ClockRef = clockRef
+	default:
+		return nil, ctx.error("restart interval or clock required")
+	}
+
+	if raw.Probability != nil {
+		p := *raw.Probability
+		if math.IsNaN(p) || p <= 0 || p > 1 {
+			return nil, ctx.error(fmt.Sprintf("restart probability %g out of range (greater than 0 up to 1)", p))
+		}
+		result.Probability = p
+	}
+
+	return result, nil
+}
+
+// validateRestart checks that a restart does not conflict with the reset.
+// Both would reset the value, only at_value wraps independently.
+func validateRestart(value ValueConfig, ctx resolveContext) error {
+	if value.Restart == nil {
+		return nil
+	}
+	if value.Reset.Type != "" && value.Reset.Type != ResetAtValue {
+		return ctx.error(fmt.Sprintf("restart not supported with reset %s", value.Reset.Type))
+	}
+	return nil
+}
+
 // validateValue validates a resolved value config
 func (r *Resolver) validateValue(value ValueConfig, ctx resolveContext) error {
+	if err := validateRestart(value, ctx); err != nil {
+		return err
+	}
+
+	// Derived and expression values have no own source
+	if value.Derive != nil || value.Expr != nil {
+		return validateTransforms(value.Transforms, ctx)
+	}
+
 	// Source required
 	if value.Source.Type == "" {
 		return ctx.error("source required")
@@ -162,5 +346,146 @@ func (r *Resolver) validateValue(value ValueConfig, ctx resolveContext) error {
 		return ctx.error("clock required in source")
 	}
 
+	return validateTransforms(value.Transforms, ctx)
+}
+
+// resolveDerive resolves derive inputs to source instances or value instances.
+// Value instances must be defined before the values referencing them.
+func (r *Resolver) resolveDerive(raw *RawDeriveConfig, ctx resolveContext) (*DeriveConfig, error) {
+	derive := &DeriveConfig{
+		Op:      DeriveOp(raw.Op),
+		Weights: slices.Clone(raw.Weights),
+	}
+
+	switch derive.Op {
+	case DeriveOpSum, DeriveOpMin, DeriveOpMax, DeriveOpWeightedSum:
+		if len(raw.Inputs) < 2 {
+			return nil, ctx.error(fmt.Sprintf("derive %s requires at least 2 inputs", derive.Op))
+		}
+	case DeriveOpDiff, DeriveOpRatio:
+		if len(raw.Inputs) != 2 {
+			return nil, ctx.error(fmt.Sprintf("derive %s requires exactly 2 inputs", derive.Op))
+		}
+	case "":
+		return nil, ctx.error("derive op required")
+	default:
+		return nil, ctx.error(fmt.Sprintf("invalid derive op: %s (must be sum, diff, ratio, min, max or weighted_sum)", derive.Op))
+	}
+
+	// Weights belong to weighted_sum only
+	if derive.Op == DeriveOpWeightedSum {
+		if len(raw.Weights) != len(raw.Inputs) {
+			return nil, ctx.error(fmt.Sprintf("derive weighted_sum requires one weight per input (got %d weights for %d inputs)",
+				len(raw.Weights), len(raw.Inputs)))
+		}
+	} else if len(raw.Weights) > 0 {
+		return nil, ctx.error("weights require derive op weighted_sum")
+	}
+
+	for _, name := range raw.Inputs {
+		input, err := r.resolveInput(name, "derive input", ctx)
+		if err != nil {
+			return nil, err
+		}
+		derive.Inputs = append(derive.Inputs, input)
+	}
+
+	return derive, nil
+}
+
+// resolveExpr parses an expression and resolves its variables to instances
+func (r *Resolver) resolveExpr(source string, ctx resolveContext) (*ExprConfig, error) {
+	expression, err := expr.Parse(source)
+	if err != nil {
+		return nil, ctx.error(fmt.Sprintf("invalid expr %q: %v", source, err))
+	}
+
+	// Inputs drive evaluation, an expression without inputs would never update
+	names := expression.Names()
+	if len(names) == 0 {
+		return nil, ctx.error(fmt.Sprintf("expr %q must reference at least one source or value instance", source))
+	}
+
+	// The builtin shadows an instance of the same name
+	if entityType := r.registeredNames[expr.TickName]; expression.UsesTick() &&
+		(entityType == "instance source" || entityType == "instance value") {
+		return nil, ctx.error(fmt.Sprintf("expr %q: %s refers to the builtin evaluation count, not %s %q (rename the instance)", source, expr.TickName, entityType, expr.TickName))
+	}
+
+	resolved := &ExprConfig{Expression: expression}
+	for _, name := range names {
+		input, err := r.resolveInput(name, "expr variable", ctx)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Inputs = append(resolved.Inputs, input)
+	}
+
+	return resolved, nil
+}
+
+// resolveInput resolves a source or value instance used as input of a
+// derived or expression value. Value instances must be defined before use.
+func (r *Resolver) resolveInput(name, role string, ctx resolveContext) (DeriveInput, error) {
+	if source, exists := r.instanceSources[name]; exists {
+		return DeriveInput{Name: name, Source: &source}, nil
+	}
+	if value, exists := r.instanceValues[name]; exists {
+		// Inputs follow every update, only exporter reads reset the window
+		if value.Reset.Type == ResetOnRead {
+			return DeriveInput{}, ctx.error(fmt.Sprintf("%s %q resets on read, which only applies to exporter reads (use on_interval or on_tick)", role, name))
+		}
+		value.ValueRef = &name
+		return DeriveInput{Name: name, Value: &value}, nil
+	}
+	if entityType, exists := r.registeredNames[name]; exists {
+		// Earlier value instances are resolved, only the value itself remains
+		if entityType == "instance value" {
+			return DeriveInput{}, ctx.error(fmt.Sprintf("%s %q cannot reference the value itself", role, name))
+		}
+		return DeriveInput{}, ctx.error(fmt.Sprintf("%s %q refers to %s (must be a source or value instance)", role, name, entityType))
+	}
+	return DeriveInput{}, ctx.error(fmt.Sprintf("%s %q not found (must be a source instance or a previously defined value instance)", role, name))
+}
+
+// validateTransforms validates transform types and their parameters
+func validateTransforms(transforms []TransformConfig, ctx resolveContext) error {
+	for _, t := range transforms {
+		switch t.Type {
+		case "":
+			return ctx.error("transform type required")
+		case "accumulate", "delta", "rate":
+		case "scale":
+			if t.Factor == nil {
+				return ctx.error("factor required for scale transform")
+			}
+		case "offset":
+			if t.Offset == nil {
+				return ctx.error("offset required for offset transform")
+			}
+		case "clamp":
+			if t.Min == nil && t.Max == nil {
+				return ctx.error("min or max required for clamp transform")
+			}
+			if t.Min != nil && t.Max != nil && *t.Min > *t.Max {
+				return ctx.error(fmt.Sprintf("min (%g) must be <= max (%g) for clamp transform", *t.Min, *t.Max))
+			}
+		case "quantize":
+			if t.Step == nil || *t.Step <= 0 {
+				return ctx.error("step must be > 0 for quantize transform")
+			}
+		case "moving_average":
+			if t.Window == nil || *t.Window < 1 {
+				return ctx.error("window must be >= 1 for moving_average transform")
+			}
+		case "ewma":
+			if t.Alpha == nil || *t.Alpha <= 0 || *t.Alpha > 1 {
+				return ctx.error("alpha must be in (0, 1] for ewma transform")
+			}
+		default:
+			return ctx.error(fmt.Sprintf("unknown transform type: %s", t.Type))
+		}
+	}
+
 	return nil
 }
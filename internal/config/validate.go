@@ -25,6 +25,11 @@ func validateRawSyntax(raw *RawConfig) error {
 			return fmt.Errorf("metric at index %d: name cannot be empty", i)
 		}
 
+		// Type and description may be inherited, checked after template resolution
+		if metric.Template != "" {
+			continue
+		}
+
 		if metric.Type == "" {
 			return fmt.Errorf("metric %q: type cannot be empty", promName)
 		}
@@ -0,0 +1,270 @@
+// Package cron implements cron schedules for calendar-based clocks.
+// Schedules use the standard five fields (minute, hour, day of month, month,
+// day of week) with an optional leading seconds field, and support the
+// usual lists, ranges, steps and month and weekday names.
+package cron
+
+import (
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// searchYears bounds the search for the next activation.
+// Eight years contain at least one February 29 in all but century years.
+const searchYears = 8
+
+// macros maps predefined schedules to their five-field form.
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// Schedule is a parsed cron schedule.
+type Schedule struct {
+	spec   string
+	second uint64 // Bit set of matching values
+	minute uint64
+	hour   uint64
+	dom    uint64
+	month  uint64
+	dow    uint64
+
+	// Day of month and day of week match if either matches,
+	// unless one of them is unrestricted
+	domStar bool
+	dowStar bool
+
+	mean time.Duration
+}
+
+// field describes the bounds and names of a schedule field.
+type field struct {
+	name     string
+	min, max int
+	names    []string // Names of consecutive values starting at min
+}
+
+var (
+	secondField = field{name: "second", min: 0, max: 59}
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: []string{
+		"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec",
+	}}
+	// 7 is Sunday like 0
+	dowField = field{name: "day of week", min: 0, max: 7, names: []string{
+		"sun", "mon", "tue", "wed", "thu", "fri", "sat",
+	}}
+)
+
+// Parse parses a cron schedule.
+//
+// Fields, with the seconds field optional:
+//
+//	[second] minute hour day-of-month month day-of-week
+//
+// Each field is *, a value, a range a-b, a step */n or a-b/n, or a comma
+// separated list of those. Predefined schedules such as @hourly and @daily
+// are supported as well.
+func Parse(spec string) (*Schedule, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 1 {
+		if expanded, ok := macros[strings.ToLower(fields[0])]; ok {
+			fields = strings.Fields(expanded)
+		}
+	}
+
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("expected 5 or 6 fields, got %d", len(fields))
+	}
+
+	s := &Schedule{spec: spec}
+	var err error
+	targets := []struct {
+		dst *uint64
+		f   field
+	}{
+		{&s.second, secondField},
+		{&s.minute, minuteField},
+		{&s.hour, hourField},
+		{&s.dom, domField},
+		{&s.month, monthField},
+		{&s.dow, dowField},
+	}
+	for i, target := range targets {
+		if *target.dst, err = target.f.parse(fields[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sunday as 7
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+		s.dow &^= 1 << 7
+	}
+	s.domStar = strings.HasPrefix(fields[3], "*")
+	s.dowStar = strings.HasPrefix(fields[5], "*")
+
+	s.mean = s.meanInterval()
+	if s.mean == 0 {
+		return nil, fmt.Errorf("schedule never fires")
+	}
+
+	return s, nil
+}
+
+// parse parses a field into a bit set of matching values.
+func (f field) parse(text string) (uint64, error) {
+	var set uint64
+	for part := range strings.SplitSeq(text, ",") {
+		rangeText, stepText, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepText)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("%s field: invalid step %q", f.name, stepText)
+			}
+			step = n
+		}
+
+		var lower, upper int
+		switch {
+		case rangeText == "*":
+			lower, upper = f.min, f.max
+		case strings.Contains(rangeText, "-"):
+			lowerText, upperText, _ := strings.Cut(rangeText, "-")
+			var err error
+			if lower, err = f.value(lowerText); err != nil {
+				return 0, err
+			}
+			if upper, err = f.value(upperText); err != nil {
+				return 0, err
+			}
+			if lower > upper {
+				return 0, fmt.Errorf("%s field: invalid range %q", f.name, rangeText)
+			}
+		default:
+			var err error
+			if lower, err = f.value(rangeText); err != nil {
+				return 0, err
+			}
+			upper = lower
+			// a/n steps from a to the end of the range
+			if hasStep {
+				upper = f.max
+			}
+		}
+
+		for v := lower; v <= upper; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+// value parses a single number or name of the field.
+func (f field) value(text string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(text, name) {
+			return f.min + i, nil
+		}
+	}
+	v, err := strconv.Atoi(text)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("%s field: invalid value %q (must be %d-%d)", f.name, text, f.min, f.max)
+	}
+	return v, nil
+}
+
+// String returns the schedule source.
+func (s *Schedule) String() string {
+	return s.spec
+}
+
+// MeanInterval returns the average time between activations.
+func (s *Schedule) MeanInterval() time.Duration {
+	return s.mean
+}
+
+// meanInterval averages activations over a full Gregorian cycle, 0 if there are none.
+// The 400 year cycle spans whole weeks, so weekday schedules average exactly.
+// Daylight saving time shifts are ignored.
+func (s *Schedule) meanInterval() time.Duration {
+	const cycleDays = 146097 // Days in 400 Gregorian years
+
+	// Days in UTC are 24 hours long
+	start := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var days int
+	for t, i := start, 0; i < cycleDays; t, i = t.Add(24*time.Hour), i+1 {
+		if s.month&(1<<uint(t.Month())) != 0 && s.dayMatches(t) {
+			days++
+		}
+	}
+
+	perDay := bits.OnesCount64(s.hour) * bits.OnesCount64(s.minute) * bits.OnesCount64(s.second)
+	if days == 0 || perDay == 0 {
+		return 0
+	}
+	// The cycle overflows time.Duration
+	return time.Duration(cycleDays * float64(24*time.Hour) / float64(days*perDay))
+}
+
+// dayMatches reports whether the day of t matches day of month and day of week.
+func (s *Schedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the first activation after t, in the location of t.
+// Returns the zero time if there is none within the search window.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(searchYears, 0, 0)
+
+	for t.Before(limit) {
+		var next time.Time
+		switch {
+		case s.month&(1<<uint(t.Month())) == 0:
+			next = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+		case !s.dayMatches(t):
+			next = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+		case s.hour&(1<<uint(t.Hour())) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+		case s.minute&(1<<uint(t.Minute())) == 0:
+			next = time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute()+1, 0, 0, loc)
+		case s.second&(1<<uint(t.Second())) == 0:
+			next = t.Add(time.Second)
+		default:
+			return t
+		}
+
+		// Wall clock times skipped or repeated by daylight saving time
+		// may normalize backwards, always make progress
+		if !next.After(t) {
+			next = t.Add(time.Second)
+		}
+		t = next
+	}
+
+	return time.Time{}
+}
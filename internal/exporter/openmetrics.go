@@ -0,0 +1,230 @@
+package exporter
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/neox5/otelbox/internal/metric"
+	"github.com/neox5/otelbox/internal/simulation"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// progressInterval is the real time between progress logs.
+const progressInterval = 5 * time.Second
+
+// OpenMetricsWriter writes timestamped samples to an OpenMetrics text file.
+// Samples are collected every interval of virtual time, like scrapes of the
+// Prometheus exporter. OpenMetrics requires the samples of a metric family
+// to be contiguous, so each family is buffered in a temporary file until
+// Close assembles the output.
+type OpenMetricsWriter struct {
+	path     string
+	gatherer prometheus.Gatherer
+	options  []expfmt.EncoderOption
+	created  bool   // Families include _created lines
+	dir      string // Temporary directory holding family buffers
+	families map[string]*familyBuffer
+	order    []string // Family names in order of first appearance
+	samples  int
+	err      error // First collection error, reported by Close
+
+	lastProgress time.Time
+}
+
+// familyBuffer holds the samples of one metric family.
+type familyBuffer struct {
+	file *os.File
+	buf  *bufio.Writer
+}
+
+// NewOpenMetricsWriter creates a writer collecting metrics every interval of virtual time.
+func NewOpenMetricsWriter(path string, interval time.Duration, metrics *metric.Registry) (*OpenMetricsWriter, error) {
+	dir, err := os.MkdirTemp("", "otelbox-backfill-")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+
+	w := &OpenMetricsWriter{
+		path:         path,
+		gatherer:     createPrometheusRegistry(metrics),
+		dir:          dir,
+		families:     make(map[string]*familyBuffer),
+		lastProgress: time.Now(),
+	}
+
+	// Restarts are only visible with _created lines, like in the scraped text format
+	if slices.ContainsFunc(metrics.Metrics(), func(m metric.Descriptor) bool {
+		return m.Restarts
+	}) {
+		w.options = append(w.options, expfmt.WithCreatedLines())
+		w.created = true
+	}
+
+	simulation.Every(interval, w.collect)
+
+	return w, nil
+}
+
+// collect appends the current samples of all metric families.
+// Called on the simulation timeline.
+func (w *OpenMetricsWriter) collect(now time.Time) {
+	if w.err != nil {
+		return
+	}
+
+	families, err := w.gatherer.Gather()
+	if err != nil {
+		w.err = fmt.Errorf("failed to gather metrics: %w", err)
+		return
+	}
+
+	for _, mf := range families {
+		if err := w.append(mf, now); err != nil {
+			w.err = err
+			return
+		}
+	}
+
+	if time.Since(w.lastProgress) >= progressInterval {
+		w.lastProgress = time.Now()
+		slog.Info("backfill progress", "time", now.Format(time.RFC3339), "samples", w.samples)
+	}
+}
+
+// append encodes a metric family collected at now into its buffer.
+// Metadata is only written with the first samples of a family.
+func (w *OpenMetricsWriter) append(mf *dto.MetricFamily, now time.Time) error {
+	var encoded bytes.Buffer
+	if _, err := expfmt.MetricFamilyToOpenMetrics(&encoded, mf, w.options...); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", mf.GetName(), err)
+	}
+	data := encoded.Bytes()
+	if w.created {
+		data = timestampCreated(data, createdName(mf), now)
+	}
+
+	family, ok := w.families[mf.GetName()]
+	if ok {
+		data = stripMetadata(data)
+	} else {
+		file, err := os.CreateTemp(w.dir, "family-")
+		if err != nil {
+			return fmt.Errorf("failed to create buffer for %s: %w", mf.GetName(), err)
+		}
+		family = &familyBuffer{file: file, buf: bufio.NewWriter(file)}
+		w.families[mf.GetName()] = family
+		w.order = append(w.order, mf.GetName())
+	}
+
+	if _, err := family.buf.Write(data); err != nil {
+		return fmt.Errorf("failed to buffer %s: %w", mf.GetName(), err)
+	}
+	w.samples += len(mf.GetMetric())
+	return nil
+}
+
+// createdName returns the name of the _created lines of a metric family.
+func createdName(mf *dto.MetricFamily) string {
+	if mf.GetType() == dto.MetricType_COUNTER {
+		return strings.TrimSuffix(mf.GetName(), "_total") + "_created"
+	}
+	return mf.GetName() + "_created"
+}
+
+// timestampCreated appends the timestamp of the samples to the _created lines
+// of an encoded family. The encoder writes them without, but every backfilled
+// sample needs one.
+func timestampCreated(data []byte, name string, now time.Time) []byte {
+	timestamp := " " + strconv.FormatFloat(float64(now.UnixMilli())/1e3, 'g', -1, 64)
+
+	var out bytes.Buffer
+	for line := range bytes.Lines(data) {
+		rest, ok := bytes.CutPrefix(line, []byte(name))
+		if ok && (bytes.HasPrefix(rest, []byte("{")) || bytes.HasPrefix(rest, []byte(" "))) {
+			out.Write(bytes.TrimSuffix(line, []byte("\n")))
+			out.WriteString(timestamp)
+			out.WriteByte('\n')
+			continue
+		}
+		out.Write(line)
+	}
+	return out.Bytes()
+}
+
+// stripMetadata removes the leading HELP, TYPE and UNIT lines of an encoded family.
+func stripMetadata(data []byte) []byte {
+	for bytes.HasPrefix(data, []byte("# ")) {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			return nil
+		}
+		data = data[i+1:]
+	}
+	return data
+}
+
+// Close writes all collected samples to the output file and removes the buffers.
+// Must be called once the timeline stopped.
+func (w *OpenMetricsWriter) Close() error {
+	defer w.Abort()
+
+	if w.err != nil {
+		return w.err
+	}
+
+	out, err := os.Create(w.path)
+	if err != nil {
+		return fmt.Errorf("failed to create output file: %w", err)
+	}
+	buf := bufio.NewWriter(out)
+
+	for _, name := range w.order {
+		if err := w.families[name].copyTo(buf); err != nil {
+			out.Close()
+			return fmt.Errorf("failed to write %s: %w", name, err)
+		}
+	}
+	if _, err := expfmt.FinalizeOpenMetrics(buf); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	if err := errors.Join(buf.Flush(), out.Close()); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
+
+	slog.Info("backfill written", "path", w.path, "families", len(w.order), "samples", w.samples)
+	return nil
+}
+
+// Abort removes the buffers without writing the output file.
+func (w *OpenMetricsWriter) Abort() {
+	for _, family := range w.families {
+		family.file.Close()
+	}
+	clear(w.families)
+	os.RemoveAll(w.dir)
+}
+
+// copyTo flushes the family buffer and copies its content to dst.
+func (f *familyBuffer) copyTo(dst io.Writer) error {
+	if err := f.buf.Flush(); err != nil {
+		return err
+	}
+	if _, err := f.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	_, err := io.Copy(dst, f.file)
+	return err
+}
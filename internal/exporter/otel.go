@@ -7,7 +7,7 @@ import (
 
 	"github.com/neox5/otelbox/internal/config"
 	"github.com/neox5/otelbox/internal/metric"
-	"github.com/neox5/simv/value"
+	"github.com/neox5/otelbox/internal/simulation"
 	"go.opentelemetry.io/otel/attribute"
 	otelmetric "go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -21,12 +21,31 @@ type OTELExporter struct {
 	instruments   []instrument
 }
 
-// instrument holds an OTEL observable instrument and its value reference.
+// instrument holds an OTEL instrument and its value reference.
+// Exactly one of intObservable, floatObservable, histogram and summary is set,
+// depending on metric and value type.
 type instrument struct {
-	counter    otelmetric.Int64ObservableCounter
-	gauge      otelmetric.Int64ObservableGauge
-	value      *value.Value[int]
-	attributes []attribute.KeyValue
+	intObservable   otelmetric.Int64Observable
+	floatObservable otelmetric.Float64Observable
+	histogram       otelmetric.Float64Histogram // Recorded on every value update
+	summary         *summaryInstruments
+	value           metric.Reader // Nil for info metrics (constant 1)
+	attributes      []attribute.KeyValue
+
+	// Stateset metrics observe intObservable once per state
+	stateSet   *simulation.StateSet
+	stateAttrs []otelmetric.ObserveOption // Per state, in stateSet.States() order
+}
+
+// summaryInstruments holds the OTEL equivalent of a summary.
+// The OTEL API has no summary instrument: quantiles are observed on a gauge
+// with a "quantile" attribute, sum and count on monotonic counters.
+type summaryInstruments struct {
+	quantiles     otelmetric.Float64ObservableGauge
+	sum           otelmetric.Float64ObservableCounter
+	count         otelmetric.Int64ObservableCounter
+	source        *simulation.Summary
+	quantileAttrs []otelmetric.ObserveOption // Per quantile, in source.Quantiles() order
 }
 
 // NewOTELExporter creates a new OTEL exporter.
@@ -41,7 +60,7 @@ func NewOTELExporter(
 	}
 
 	// Create meter provider
-	meterProvider, err := createMeterProvider(cfg, res)
+	meterProvider, err := createMeterProvider(cfg, res, createOTELViews(metrics), createOTELStartTimes(metrics))
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,282 @@
+package exporter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/otelbox/internal/simulation"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	colmetricpb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricpb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// backupTimeFormat names rotated files, sorting chronologically.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// otlpJSON encodes payloads in the OTLP JSON encoding, which requires enum numbers.
+var otlpJSON = protojson.MarshalOptions{UseEnumNumbers: true}
+
+// deterministic encodes attributes into stable sort keys.
+var deterministic = proto.MarshalOptions{Deterministic: true}
+
+// fileExporter writes OTLP payloads as JSON lines, like the collector's file exporter.
+// Payloads are encoded by the OTLP HTTP exporter and written by fileTransport
+// instead of being sent, so each line holds exactly what a collector receives.
+type fileExporter struct {
+	sdkmetric.Exporter
+	file *rotatingFile
+}
+
+// createFileExporter creates an OTLP exporter writing to a file.
+func createFileExporter(cfg *config.OTELExportConfig) (sdkmetric.Exporter, error) {
+	file, err := newRotatingFile(cfg.File.Path, cfg.File.Rotation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open OTLP file: %w", err)
+	}
+
+	exporter, err := otlpmetrichttp.New(context.Background(),
+		otlpmetrichttp.WithEndpoint("localhost"), // Never connected, requests go to fileTransport
+		otlpmetrichttp.WithInsecure(),
+		otlpmetrichttp.WithCompression(otlpmetrichttp.NoCompression),
+		otlpmetrichttp.WithRetry(otlpmetrichttp.RetryConfig{Enabled: false}),
+		otlpmetrichttp.WithHTTPClient(&http.Client{Transport: &fileTransport{file: file}}),
+	)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to create OTLP file exporter: %w", err)
+	}
+
+	return &fileExporter{Exporter: exporter, file: file}, nil
+}
+
+// Shutdown shuts down the exporter and closes the file.
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.file.Close())
+}
+
+// fileTransport answers OTLP HTTP requests by writing their payload to a file.
+type fileTransport struct {
+	file *rotatingFile
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *fileTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	var request colmetricpb.ExportMetricsServiceRequest
+	if err := proto.Unmarshal(body, &request); err != nil {
+		return nil, fmt.Errorf("failed to decode OTLP payload: %w", err)
+	}
+	sortDataPoints(&request)
+	encoded, err := otlpJSON.Marshal(&request)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode OTLP JSON: %w", err)
+	}
+
+	// protojson varies whitespace between builds, compact output is stable
+	var line bytes.Buffer
+	if err := json.Compact(&line, encoded); err != nil {
+		return nil, fmt.Errorf("failed to encode OTLP JSON: %w", err)
+	}
+	line.WriteByte('\n')
+
+	if err := t.file.Write(line.Bytes()); err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+// sortDataPoints orders the data points of each metric by their attributes.
+// The SDK emits them in map order, sorted lines can be diffed between runs.
+func sortDataPoints(request *colmetricpb.ExportMetricsServiceRequest) {
+	for _, rm := range request.GetResourceMetrics() {
+		for _, sm := range rm.GetScopeMetrics() {
+			for _, m := range sm.GetMetrics() {
+				switch data := m.GetData().(type) {
+				case *metricpb.Metric_Gauge:
+					sortByAttributes(data.Gauge.GetDataPoints(), (*metricpb.NumberDataPoint).GetAttributes)
+				case *metricpb.Metric_Sum:
+					sortByAttributes(data.Sum.GetDataPoints(), (*metricpb.NumberDataPoint).GetAttributes)
+				case *metricpb.Metric_Histogram:
+					sortByAttributes(data.Histogram.GetDataPoints(), (*metricpb.HistogramDataPoint).GetAttributes)
+				case *metricpb.Metric_ExponentialHistogram:
+					sortByAttributes(data.ExponentialHistogram.GetDataPoints(), (*metricpb.ExponentialHistogramDataPoint).GetAttributes)
+				}
+			}
+		}
+	}
+}
+
+// sortByAttributes sorts points by their encoded attributes.
+func sortByAttributes[P any](points []P, attributes func(P) []*commonpb.KeyValue) {
+	keys := make(map[any]string, len(points))
+	for _, point := range points {
+		var key []byte
+		for _, kv := range attributes(point) {
+			key, _ = deterministic.MarshalAppend(key, kv)
+		}
+		keys[point] = string(key)
+	}
+	slices.SortStableFunc(points, func(a, b P) int {
+		return strings.Compare(keys[a], keys[b])
+	})
+}
+
+// rotatingFile appends lines to a file. Once the file would exceed its size
+// or is older than the rotation interval, it is renamed to a backup with the
+// rotation time in its name and a new file is started. Ages and backup names
+// use simulation time, so virtual time rotates by virtual time.
+type rotatingFile struct {
+	path     string
+	rotation config.RotationConfig
+
+	mu     sync.Mutex
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// newRotatingFile opens path for appending.
+func newRotatingFile(path string, rotation config.RotationConfig) (*rotatingFile, error) {
+	f := &rotatingFile{path: path, rotation: rotation}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// open opens the file, existing content counts towards its size.
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.opened = simulation.Now()
+	return nil
+}
+
+// Write appends a line, rotating the file first if required.
+func (f *rotatingFile) Write(line []byte) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return os.ErrClosed
+	}
+
+	now := simulation.Now()
+	if f.size > 0 && f.due(now, len(line)) {
+		if err := f.rotate(now); err != nil {
+			return fmt.Errorf("failed to rotate OTLP file: %w", err)
+		}
+	}
+
+	n, err := f.file.Write(line)
+	f.size += int64(n)
+	return err
+}
+
+// due reports whether the file must be rotated before writing n bytes.
+func (f *rotatingFile) due(now time.Time, n int) bool {
+	maxSize := int64(f.rotation.MaxMegabytes) * 1024 * 1024
+	if maxSize > 0 && f.size+int64(n) > maxSize {
+		return true
+	}
+	return f.rotation.Interval > 0 && !now.Before(f.opened.Add(f.rotation.Interval))
+}
+
+// rotate moves the file to a backup, starts a new one and removes old backups.
+func (f *rotatingFile) rotate(now time.Time) error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	f.file = nil
+
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(f.path, ext) + "-"
+	backup := prefix + now.UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	if f.rotation.MaxBackups == 0 {
+		return nil
+	}
+	return removeBackups(prefix, ext, f.rotation.MaxBackups)
+}
+
+// removeBackups removes all but the newest keep backups.
+func removeBackups(prefix, ext string, keep int) error {
+	matches, err := filepath.Glob(prefix + "*" + ext)
+	if err != nil {
+		return err
+	}
+
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimPrefix(match, prefix), ext)
+		if _, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, match)
+		}
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+
+	slices.Sort(backups)
+	var errs []error
+	for _, backup := range backups[:len(backups)-keep] {
+		errs = append(errs, os.Remove(backup))
+	}
+	return errors.Join(errs...)
+}
+
+// Close closes the file.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
 	"sort"
+	"strconv"
 
 	"github.com/neox5/otelbox/internal/metric"
 	"go.opentelemetry.io/otel/attribute"
@@ -15,7 +18,7 @@ import (
 func registerOTELInstruments(e *OTELExporter, metrics *metric.Registry) error {
 	var instruments []instrument
 
-	for _, m := range metrics.Metrics() {
+	for _, m := range metrics.ReadMetrics() {
 		// Convert attributes map to OTEL attributes
 		attrs := make([]attribute.KeyValue, 0, len(m.Attributes))
 		for key, val := range m.Attributes {
@@ -27,26 +30,17 @@ func registerOTELInstruments(e *OTELExporter, metrics *metric.Registry) error {
 			attributes: attrs,
 		}
 
+		var err error
 		switch m.Type {
-		case metric.MetricTypeCounter:
-			counter, err := e.meter.Int64ObservableCounter(
-				m.OTELName,
-				otelmetric.WithDescription(m.Description),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create counter %q: %w", m.OTELName, err)
-			}
-			inst.counter = counter
-
-		case metric.MetricTypeGauge:
-			gauge, err := e.meter.Int64ObservableGauge(
-				m.OTELName,
-				otelmetric.WithDescription(m.Description),
-			)
-			if err != nil {
-				return fmt.Errorf("failed to create gauge %q: %w", m.OTELName, err)
-			}
-			inst.gauge = gauge
+		case metric.MetricTypeHistogram:
+			err = createOTELHistogram(e, m, &inst)
+		case metric.MetricTypeSummary:
+			err = createOTELSummary(e, m, &inst)
+		default:
+			err = createOTELObservable(e, m, &inst)
+		}
+		if err != nil {
+			return err
 		}
 
 		instruments = append(instruments, inst)
@@ -61,6 +55,7 @@ func registerOTELInstruments(e *OTELExporter, metrics *metric.Registry) error {
 		slog.Debug("registered otel metric",
 			"name", m.OTELName,
 			"type", m.Type,
+			"value_type", m.ValueType,
 			"attributes", fmt.Sprintf("[%s]", attrPairs))
 	}
 
@@ -76,16 +71,181 @@ func registerOTELInstruments(e *OTELExporter, metrics *metric.Registry) error {
 	return nil
 }
 
+// createOTELObservable creates the observable instrument matching metric and value type.
+func createOTELObservable(e *OTELExporter, m metric.Descriptor, inst *instrument) error {
+	var err error
+
+	switch m.ValueType {
+	case metric.ValueTypeFloat:
+		switch m.Type {
+		case metric.MetricTypeCounter:
+			inst.floatObservable, err = e.meter.Float64ObservableCounter(
+				m.OTELName,
+				otelmetric.WithDescription(m.Description),
+			)
+		case metric.MetricTypeGauge:
+			inst.floatObservable, err = e.meter.Float64ObservableGauge(
+				m.OTELName,
+				otelmetric.WithDescription(m.Description),
+			)
+		case metric.MetricTypeUpDownCounter:
+			inst.floatObservable, err = e.meter.Float64ObservableUpDownCounter(
+				m.OTELName,
+				otelmetric.WithDescription(m.Description),
+			)
+		}
+
+	default:
+		switch m.Type {
+		case metric.MetricTypeCounter:
+			inst.intObservable, err = e.meter.Int64ObservableCounter(
+				m.OTELName,
+				otelmetric.WithDescription(m.Description),
+			)
+		case metric.MetricTypeGauge, metric.MetricTypeInfo, metric.MetricTypeStateSet:
+			inst.intObservable, err = e.meter.Int64ObservableGauge(
+				m.OTELName,
+				otelmetric.WithDescription(m.Description),
+			)
+		case metric.MetricTypeUpDownCounter:
+			inst.intObservable, err = e.meter.Int64ObservableUpDownCounter(
+				m.OTELName,
+				otelmetric.WithDescription(m.Description),
+			)
+		}
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to create %s %q: %w", m.Type, m.OTELName, err)
+	}
+
+	// Statesets observe one series per state, attribute sets are constant
+	if m.StateSet != nil {
+		inst.stateSet = m.StateSet
+		for _, state := range m.StateSet.States() {
+			attrs := append(slices.Clone(inst.attributes), attribute.String(m.StateLabel, state))
+			inst.stateAttrs = append(inst.stateAttrs, otelmetric.WithAttributeSet(attribute.NewSet(attrs...)))
+		}
+	}
+
+	return nil
+}
+
+// createOTELHistogram creates a histogram with the configured bucket bounds.
+// Exponential histograms get their aggregation from a view (see createOTELViews).
+// Every value update is recorded as an observation.
+func createOTELHistogram(e *OTELExporter, m metric.Descriptor, inst *instrument) error {
+	opts := []otelmetric.Float64HistogramOption{
+		otelmetric.WithDescription(m.Description),
+	}
+	if m.Histogram != nil {
+		opts = append(opts, otelmetric.WithExplicitBucketBoundaries(m.Histogram.Bounds()...))
+	}
+
+	histogram, err := e.meter.Float64Histogram(m.OTELName, opts...)
+	if err != nil {
+		return fmt.Errorf("failed to create %s %q: %w", m.Type, m.OTELName, err)
+	}
+	inst.histogram = histogram
+
+	// Attribute set is constant, build the option once
+	attrs := otelmetric.WithAttributeSet(attribute.NewSet(inst.attributes...))
+	m.Updates.AddObserver(func(v float64) {
+		histogram.Record(context.Background(), v, attrs)
+	})
+
+	return nil
+}
+
+// createOTELSummary creates the gauge and counters representing a summary.
+// Instruments: <name> (gauge, quantile attribute), <name>.sum and <name>.count.
+func createOTELSummary(e *OTELExporter, m metric.Descriptor, inst *instrument) error {
+	quantiles, err := e.meter.Float64ObservableGauge(
+		m.OTELName,
+		otelmetric.WithDescription(m.Description),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create %s %q: %w", m.Type, m.OTELName, err)
+	}
+
+	sum, err := e.meter.Float64ObservableCounter(
+		m.OTELName+".sum",
+		otelmetric.WithDescription(m.Description+" (sum of observations)"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create %s %q: %w", m.Type, m.OTELName+".sum", err)
+	}
+
+	count, err := e.meter.Int64ObservableCounter(
+		m.OTELName+".count",
+		otelmetric.WithDescription(m.Description+" (number of observations)"),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create %s %q: %w", m.Type, m.OTELName+".count", err)
+	}
+
+	// Attribute sets are constant, build the options once
+	var quantileAttrs []otelmetric.ObserveOption
+	for _, q := range m.Summary.Quantiles() {
+		attrs := append(slices.Clone(inst.attributes),
+			attribute.String("quantile", strconv.FormatFloat(q, 'g', -1, 64)))
+		quantileAttrs = append(quantileAttrs, otelmetric.WithAttributeSet(attribute.NewSet(attrs...)))
+	}
+
+	inst.summary = &summaryInstruments{
+		quantiles:     quantiles,
+		sum:           sum,
+		count:         count,
+		source:        m.Summary,
+		quantileAttrs: quantileAttrs,
+	}
+
+	return nil
+}
+
+// observeOTELSummary observes quantiles, sum and count from a single snapshot.
+func observeOTELSummary(observer otelmetric.Observer, inst instrument) {
+	s := inst.summary
+	snapshot := s.source.Snapshot()
+
+	for i, q := range s.source.Quantiles() {
+		val := snapshot.Quantiles[q]
+		if math.IsNaN(val) {
+			continue // No observations yet
+		}
+		observer.ObserveFloat64(s.quantiles, val, s.quantileAttrs[i])
+	}
+
+	attrs := otelmetric.WithAttributes(inst.attributes...)
+	observer.ObserveFloat64(s.sum, snapshot.Sum, attrs)
+	observer.ObserveInt64(s.count, int64(snapshot.Count), attrs)
+}
+
+// observeOTELStateSet observes 1 for the active state and 0 for all others.
+func observeOTELStateSet(observer otelmetric.Observer, inst instrument) {
+	active := inst.stateSet.Active()
+	for i := range inst.stateAttrs {
+		var val int64
+		if i == active {
+			val = 1
+		}
+		observer.ObserveInt64(inst.intObservable, val, inst.stateAttrs[i])
+	}
+}
+
 // registerOTELCallback registers the observation callback for all instruments.
 func registerOTELCallback(e *OTELExporter) error {
 	// Collect all observables for callback registration
 	var observables []otelmetric.Observable
 	for _, inst := range e.instruments {
-		if inst.counter != nil {
-			observables = append(observables, inst.counter)
+		if inst.intObservable != nil {
+			observables = append(observables, inst.intObservable)
+		}
+		if inst.floatObservable != nil {
+			observables = append(observables, inst.floatObservable)
 		}
-		if inst.gauge != nil {
-			observables = append(observables, inst.gauge)
+		if inst.summary != nil {
+			observables = append(observables, inst.summary.quantiles, inst.summary.sum, inst.summary.count)
 		}
 	}
 
@@ -95,13 +255,28 @@ func registerOTELCallback(e *OTELExporter) error {
 			slog.Debug("otel push", "metrics", len(e.instruments))
 
 			for _, inst := range e.instruments {
-				val := int64(inst.value.Value()) // Triggers reset_on_read if configured
-				if inst.counter != nil {
-					observer.ObserveInt64(inst.counter, val,
+				if inst.histogram != nil {
+					continue // Recorded synchronously
+				}
+				if inst.summary != nil {
+					observeOTELSummary(observer, inst)
+					continue
+				}
+				if inst.stateSet != nil {
+					observeOTELStateSet(observer, inst)
+					continue
+				}
+
+				val := 1.0 // Info metrics are constant
+				if inst.value != nil {
+					val = inst.value.Value() // Resets the window of this exporter for reset_on_read
+				}
+				if inst.intObservable != nil {
+					observer.ObserveInt64(inst.intObservable, int64(math.Round(val)),
 						otelmetric.WithAttributes(inst.attributes...))
 				}
-				if inst.gauge != nil {
-					observer.ObserveInt64(inst.gauge, val,
+				if inst.floatObservable != nil {
+					observer.ObserveFloat64(inst.floatObservable, val,
 						otelmetric.WithAttributes(inst.attributes...))
 				}
 			}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/otelbox/internal/metric"
+	"github.com/neox5/otelbox/internal/simulation"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
@@ -12,9 +14,13 @@ import (
 )
 
 // createMeterProvider creates an OTEL meter provider with OTLP exporter.
+// Start times of series with lifetimes follow restarts (see startTimeExporter).
+// In virtual time metrics are exported on the simulation timeline (see virtualReader).
 func createMeterProvider(
 	cfg *config.OTELExportConfig,
 	res *resource.Resource,
+	views []sdkmetric.View,
+	lifetimes map[seriesKey]metric.Lifetime,
 ) (*sdkmetric.MeterProvider, error) {
 	// Create exporter based on transport type
 	var exporter sdkmetric.Exporter
@@ -25,6 +31,8 @@ func createMeterProvider(
 		exporter, err = createGRPCExporter(cfg)
 	case "http":
 		exporter, err = createHTTPExporter(cfg)
+	case "file":
+		exporter, err = createFileExporter(cfg)
 	default:
 		return nil, fmt.Errorf("unsupported transport: %s", cfg.Transport)
 	}
@@ -33,21 +41,55 @@ func createMeterProvider(
 		return nil, err
 	}
 
-	// Create periodic reader with push interval
-	reader := sdkmetric.NewPeriodicReader(
-		exporter,
-		sdkmetric.WithInterval(cfg.Interval.Push),
-	)
+	if lifetimes != nil {
+		exporter = &startTimeExporter{Exporter: exporter, lifetimes: lifetimes}
+	}
+
+	// Create reader with push interval
+	var reader sdkmetric.Reader
+	if simulation.VirtualTime() {
+		reader = newVirtualReader(exporter, cfg.Interval.Push)
+	} else {
+		reader = sdkmetric.NewPeriodicReader(
+			exporter,
+			sdkmetric.WithInterval(cfg.Interval.Push),
+		)
+	}
 
 	// Create meter provider
 	meterProvider := sdkmetric.NewMeterProvider(
 		sdkmetric.WithResource(res),
 		sdkmetric.WithReader(reader),
+		sdkmetric.WithView(views...),
 	)
 
 	return meterProvider, nil
 }
 
+// createOTELViews creates views selecting the exponential aggregation for
+// exponential histogram metrics. Instruments are matched by name.
+func createOTELViews(metrics *metric.Registry) []sdkmetric.View {
+	var views []sdkmetric.View
+
+	for _, m := range metrics.Metrics() {
+		if m.ExponentialHistogram == nil {
+			continue
+		}
+
+		views = append(views, sdkmetric.NewView(
+			sdkmetric.Instrument{Name: m.OTELName},
+			sdkmetric.Stream{
+				Aggregation: sdkmetric.AggregationBase2ExponentialHistogram{
+					MaxSize:  int32(m.ExponentialHistogram.MaxBuckets()),
+					MaxScale: int32(m.ExponentialHistogram.MaxScale()),
+				},
+			},
+		))
+	}
+
+	return views
+}
+
 // createGRPCExporter creates an OTLP gRPC exporter.
 func createGRPCExporter(cfg *config.OTELExportConfig) (sdkmetric.Exporter, error) {
 	opts := []otlpmetricgrpc.Option{
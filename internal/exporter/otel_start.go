@@ -0,0 +1,86 @@
+package exporter
+
+import (
+	"context"
+
+	"github.com/neox5/otelbox/internal/metric"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// seriesKey identifies a series by instrument name and attributes.
+type seriesKey struct {
+	name  string
+	attrs attribute.Distinct
+}
+
+// startTimeExporter moves the start time of cumulative sums to the last restart.
+// The SDK fixes the start time of observable instruments when they are created,
+// so restarts of the simulated process would not be visible otherwise.
+type startTimeExporter struct {
+	sdkmetric.Exporter
+	lifetimes map[seriesKey]metric.Lifetime
+}
+
+// createOTELStartTimes collects the lifetimes of restarting metrics.
+// Returns nil if no metric restarts.
+func createOTELStartTimes(metrics *metric.Registry) map[seriesKey]metric.Lifetime {
+	var lifetimes map[seriesKey]metric.Lifetime
+
+	for _, m := range metrics.Metrics() {
+		if !m.Restarts {
+			continue
+		}
+
+		attrs := make([]attribute.KeyValue, 0, len(m.Attributes))
+		for key, val := range m.Attributes {
+			attrs = append(attrs, attribute.String(key, val))
+		}
+
+		if lifetimes == nil {
+			lifetimes = make(map[seriesKey]metric.Lifetime)
+		}
+		set := attribute.NewSet(attrs...)
+		lifetimes[seriesKey{name: m.OTELName, attrs: set.Equivalent()}] = m.Lifetime
+	}
+
+	return lifetimes
+}
+
+// Export implements sdkmetric.Exporter.
+func (e *startTimeExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	for i := range rm.ScopeMetrics {
+		for j := range rm.ScopeMetrics[i].Metrics {
+			m := &rm.ScopeMetrics[i].Metrics[j]
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				adjustStartTimes(e.lifetimes, m.Name, data.Temporality, data.DataPoints)
+			case metricdata.Sum[float64]:
+				adjustStartTimes(e.lifetimes, m.Name, data.Temporality, data.DataPoints)
+			}
+		}
+	}
+	return e.Exporter.Export(ctx, rm)
+}
+
+// adjustStartTimes sets the start time of cumulative data points to the last restart.
+func adjustStartTimes[N int64 | float64](
+	lifetimes map[seriesKey]metric.Lifetime,
+	name string,
+	temporality metricdata.Temporality,
+	points []metricdata.DataPoint[N],
+) {
+	if temporality != metricdata.CumulativeTemporality {
+		return
+	}
+	for i := range points {
+		lifetime, ok := lifetimes[seriesKey{name: name, attrs: points[i].Attributes.Equivalent()}]
+		if !ok {
+			continue
+		}
+		if created := lifetime.Created(); created.After(points[i].StartTime) {
+			points[i].StartTime = created
+		}
+	}
+}
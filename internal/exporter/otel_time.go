@@ -0,0 +1,111 @@
+package exporter
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/neox5/otelbox/internal/simulation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// virtualReader collects and exports metrics on the simulation timeline.
+// Data points carry virtual timestamps: the SDK stamps them with real time,
+// which has no relation to the simulated time in virtual time.
+type virtualReader struct {
+	*sdkmetric.ManualReader
+	exporter sdkmetric.Exporter
+
+	mu   sync.Mutex // Serializes exports of the timeline and shutdown
+	last time.Time  // Virtual time of the last export
+}
+
+// newVirtualReader creates a reader exporting every push interval of virtual time.
+func newVirtualReader(exporter sdkmetric.Exporter, interval time.Duration) *virtualReader {
+	r := &virtualReader{
+		ManualReader: sdkmetric.NewManualReader(),
+		exporter:     exporter,
+	}
+
+	simulation.Every(interval, func(now time.Time) {
+		if err := r.export(context.Background(), now); err != nil {
+			slog.Warn("otel export failed", "error", err)
+		}
+	})
+
+	return r
+}
+
+// export collects all metrics and exports them as of virtual time now.
+func (r *virtualReader) export(ctx context.Context, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Shutdown at the end of virtual time follows the export of the end
+	if now.Equal(r.last) {
+		return nil
+	}
+	r.last = now
+
+	var rm metricdata.ResourceMetrics
+	if err := r.Collect(ctx, &rm); err != nil {
+		if errors.Is(err, sdkmetric.ErrReaderShutdown) {
+			return nil
+		}
+		return err
+	}
+
+	setPointTimes(&rm, simulation.TimelineStart(), now)
+	return r.exporter.Export(ctx, &rm)
+}
+
+// Shutdown exports the final state and shuts down reader and exporter.
+func (r *virtualReader) Shutdown(ctx context.Context) error {
+	return errors.Join(
+		r.export(ctx, simulation.Now()),
+		r.ManualReader.Shutdown(ctx),
+		r.exporter.Shutdown(ctx),
+	)
+}
+
+// setPointTimes sets the start and timestamp of all data points.
+func setPointTimes(rm *metricdata.ResourceMetrics, start, now time.Time) {
+	for i := range rm.ScopeMetrics {
+		for j := range rm.ScopeMetrics[i].Metrics {
+			switch data := rm.ScopeMetrics[i].Metrics[j].Data.(type) {
+			case metricdata.Gauge[int64]:
+				setDataPointTimes(data.DataPoints, start, now)
+			case metricdata.Gauge[float64]:
+				setDataPointTimes(data.DataPoints, start, now)
+			case metricdata.Sum[int64]:
+				setDataPointTimes(data.DataPoints, start, now)
+			case metricdata.Sum[float64]:
+				setDataPointTimes(data.DataPoints, start, now)
+			case metricdata.Histogram[float64]:
+				for k := range data.DataPoints {
+					data.DataPoints[k].StartTime = start
+					data.DataPoints[k].Time = now
+				}
+			case metricdata.ExponentialHistogram[float64]:
+				for k := range data.DataPoints {
+					data.DataPoints[k].StartTime = start
+					data.DataPoints[k].Time = now
+				}
+			}
+		}
+	}
+}
+
+// setDataPointTimes sets the start and timestamp of gauge and sum data points.
+// Start times are only set where the SDK set them.
+func setDataPointTimes[N int64 | float64](points []metricdata.DataPoint[N], start, now time.Time) {
+	for i := range points {
+		if !points[i].StartTime.IsZero() {
+			points[i].StartTime = start
+		}
+		points[i].Time = now
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"time"
 
 	"github.com/neox5/otelbox/internal/metric"
@@ -29,9 +30,15 @@ func NewPrometheusExporter(
 	// Create registry
 	promRegistry := createPrometheusRegistry(metrics)
 
+	// Restarts are only visible to text format scrapers with _created lines,
+	// which add a series per counter, so they are enabled on demand
+	createdSamples := slices.ContainsFunc(metrics.Metrics(), func(m metric.Descriptor) bool {
+		return m.Restarts
+	})
+
 	// Setup HTTP server
 	addr := fmt.Sprintf(":%d", port)
-	server := createHTTPServer(addr, path, promRegistry, internalMetricsEnabled)
+	server := createHTTPServer(addr, path, promRegistry, internalMetricsEnabled, createdSamples)
 
 	return &PrometheusExporter{
 		addr:         addr,
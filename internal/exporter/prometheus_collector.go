@@ -3,10 +3,12 @@ package exporter
 import (
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
 	"sort"
 
 	"github.com/neox5/otelbox/internal/metric"
-	"github.com/neox5/simv/value"
+	"github.com/neox5/otelbox/internal/simulation"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
@@ -14,8 +16,17 @@ import (
 type metricDescriptor struct {
 	desc        *prometheus.Desc
 	valueType   prometheus.ValueType
-	value       *value.Value[int]
+	value       metric.Reader
+	lifetime    metric.Lifetime // Created timestamp of counters
+	integer     bool            // Round to nearest integer (value_type: int)
 	labelValues []string
+
+	// Set depending on metric type, value is not read
+	info                 bool // Constant 1
+	histogram            *simulation.Histogram
+	exponentialHistogram *simulation.ExponentialHistogram
+	summary              *simulation.Summary
+	stateSet             *simulation.StateSet // Last label holds the state name
 }
 
 // collector implements prometheus.Collector to read simv values on scrape.
@@ -27,12 +38,16 @@ type collector struct {
 func newCollector(metrics *metric.Registry) *collector {
 	var descriptors []metricDescriptor
 
-	for _, m := range metrics.Metrics() {
+	for _, m := range metrics.ReadMetrics() {
 		var valueType prometheus.ValueType
 		switch m.Type {
 		case metric.MetricTypeCounter:
 			valueType = prometheus.CounterValue
-		case metric.MetricTypeGauge:
+		case metric.MetricTypeGauge, metric.MetricTypeUpDownCounter:
+			// Prometheus has no non-monotonic sum, gauge is the closest type
+			valueType = prometheus.GaugeValue
+		case metric.MetricTypeInfo, metric.MetricTypeStateSet:
+			// Info and stateset only exist in OpenMetrics, exposed as 0/1 gauges
 			valueType = prometheus.GaugeValue
 		}
 
@@ -49,16 +64,30 @@ func newCollector(metrics *metric.Registry) *collector {
 			labelValues[i] = m.Attributes[name]
 		}
 
+		// State label is filled per state on collect
+		descLabelNames := labelNames
+		if m.StateSet != nil {
+			descLabelNames = append(slices.Clone(labelNames), m.StateLabel)
+		}
+
 		descriptors = append(descriptors, metricDescriptor{
 			desc: prometheus.NewDesc(
 				m.PrometheusName,
 				m.Description,
-				labelNames,
+				descLabelNames,
 				nil, // No constant labels
 			),
 			valueType:   valueType,
 			value:       m.Value,
+			lifetime:    m.Lifetime,
+			integer:     m.ValueType == metric.ValueTypeInt,
 			labelValues: labelValues,
+
+			histogram:            m.Histogram,
+			exponentialHistogram: m.ExponentialHistogram,
+			summary:              m.Summary,
+			info:                 m.Type == metric.MetricTypeInfo,
+			stateSet:             m.StateSet,
 		})
 
 		// Build label key=value pairs for logging
@@ -86,18 +115,177 @@ func (c *collector) Describe(ch chan<- *prometheus.Desc) {
 }
 
 // Collect reads simv values and sends metrics to the channel.
-// This is called on each Prometheus scrape.
+// This is called on each Prometheus scrape. In virtual time samples carry
+// the virtual time of the scrape as explicit timestamp.
 func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	if !simulation.VirtualTime() {
+		c.collect(ch)
+		return
+	}
+
+	now := simulation.Now()
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		defer close(metrics)
+		c.collect(metrics)
+	}()
+	for metric := range metrics {
+		ch <- prometheus.NewMetricWithTimestamp(now, metric)
+	}
+}
+
+// collect reads simv values and sends metrics to the channel.
+func (c *collector) collect(ch chan<- prometheus.Metric) {
 	for _, m := range c.descriptors {
-		// Read value from simv (may trigger reset for reset_on_read)
-		val := float64(m.value.Value())
+		if m.histogram != nil {
+			c.collectHistogram(ch, m)
+			continue
+		}
+		if m.exponentialHistogram != nil {
+			c.collectNativeHistogram(ch, m)
+			continue
+		}
+		if m.summary != nil {
+			c.collectSummary(ch, m)
+			continue
+		}
+		if m.stateSet != nil {
+			c.collectStateSet(ch, m)
+			continue
+		}
+
+		// Read value through the cursor of this exporter (may reset its window for reset_on_read)
+		val := 1.0
+		if !m.info {
+			val = m.value.Value()
+		}
+		if m.integer {
+			val = math.Round(val)
+		}
 
 		// Create and send metric with current value and labels
+		var metric prometheus.Metric
+		var err error
+		if m.valueType == prometheus.CounterValue && m.lifetime != nil {
+			// Created timestamp moves with restarts, signals the counter reset
+			metric, err = prometheus.NewConstMetricWithCreatedTimestamp(
+				m.desc,
+				m.valueType,
+				val,
+				m.lifetime.Created(),
+				m.labelValues...,
+			)
+		} else {
+			metric, err = prometheus.NewConstMetric(
+				m.desc,
+				m.valueType,
+				val,
+				m.labelValues...,
+			)
+		}
+		if err != nil {
+			continue
+		}
+
+		ch <- metric
+	}
+}
+
+// collectHistogram sends the current histogram snapshot with cumulative buckets.
+func (c *collector) collectHistogram(ch chan<- prometheus.Metric, m metricDescriptor) {
+	snapshot := m.histogram.Snapshot()
+
+	buckets := make(map[float64]uint64, len(snapshot.Bounds))
+	var cumulative uint64
+	for i, bound := range snapshot.Bounds {
+		cumulative += snapshot.Counts[i]
+		buckets[bound] = cumulative
+	}
+
+	metric, err := prometheus.NewConstHistogram(
+		m.desc,
+		snapshot.Count,
+		snapshot.Sum,
+		buckets,
+		m.labelValues...,
+	)
+	if err != nil {
+		return
+	}
+
+	ch <- metric
+}
+
+// collectNativeHistogram sends the current exponential histogram snapshot as native histogram.
+// Native histograms are only served when the scraper negotiates the protobuf format.
+func (c *collector) collectNativeHistogram(ch chan<- prometheus.Metric, m metricDescriptor) {
+	snapshot := m.exponentialHistogram.Snapshot().Downscale(nativeHistogramMaxSchema)
+
+	metric, err := prometheus.NewConstNativeHistogram(
+		m.desc,
+		snapshot.Count,
+		snapshot.Sum,
+		nativeHistogramBuckets(snapshot.Positive),
+		nativeHistogramBuckets(snapshot.Negative),
+		snapshot.ZeroCount,
+		int32(snapshot.Scale),
+		0, // Zero bucket only holds exact zeros
+		snapshot.Created,
+		m.labelValues...,
+	)
+	if err != nil {
+		return
+	}
+
+	ch <- metric
+}
+
+// nativeHistogramMaxSchema is the highest resolution supported by Prometheus native histograms.
+const nativeHistogramMaxSchema = 8
+
+// nativeHistogramBuckets converts OTEL bucket indexes to Prometheus native histogram indexes.
+// OTEL bucket i covers (base^i, base^(i+1)], Prometheus bucket i covers (base^(i-1), base^i].
+func nativeHistogramBuckets(counts map[int]uint64) map[int]int64 {
+	buckets := make(map[int]int64, len(counts))
+	for idx, count := range counts {
+		buckets[idx+1] = int64(count)
+	}
+	return buckets
+}
+
+// collectSummary sends the current summary snapshot.
+func (c *collector) collectSummary(ch chan<- prometheus.Metric, m metricDescriptor) {
+	snapshot := m.summary.Snapshot()
+
+	metric, err := prometheus.NewConstSummary(
+		m.desc,
+		snapshot.Count,
+		snapshot.Sum,
+		snapshot.Quantiles,
+		m.labelValues...,
+	)
+	if err != nil {
+		return
+	}
+
+	ch <- metric
+}
+
+// collectStateSet sends one series per state, 1 for the active state and 0 otherwise.
+func (c *collector) collectStateSet(ch chan<- prometheus.Metric, m metricDescriptor) {
+	active := m.stateSet.Active()
+
+	for i, state := range m.stateSet.States() {
+		val := 0.0
+		if i == active {
+			val = 1
+		}
+
 		metric, err := prometheus.NewConstMetric(
 			m.desc,
 			m.valueType,
 			val,
-			m.labelValues...,
+			append(slices.Clone(m.labelValues), state)...,
 		)
 		if err != nil {
 			continue
@@ -9,11 +9,13 @@ import (
 )
 
 // createHTTPServer creates an HTTP server for Prometheus metrics.
+// createdSamples adds _created lines to the OpenMetrics text format.
 func createHTTPServer(
 	addr string,
 	path string,
 	promRegistry *prometheus.Registry,
 	internalMetricsEnabled bool,
+	createdSamples bool,
 ) *http.Server {
 	mux := http.NewServeMux()
 
@@ -21,7 +23,8 @@ func createHTTPServer(
 	baseHandler := promhttp.HandlerFor(
 		promRegistry,
 		promhttp.HandlerOpts{
-			EnableOpenMetrics: true,
+			EnableOpenMetrics:                   true,
+			EnableOpenMetricsTextCreatedSamples: createdSamples,
 		},
 	)
 
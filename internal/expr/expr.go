@@ -0,0 +1,184 @@
+// Package expr implements the small expression language of expression values.
+// Expressions combine named variables with arithmetic, comparisons, logical
+// operators, conditionals and a fixed set of functions. They have no side
+// effects and always terminate.
+package expr
+
+import (
+	"math"
+	"slices"
+)
+
+// TickName is the builtin variable holding the evaluation count.
+const TickName = "tick"
+
+// Expression is a parsed expression.
+type Expression struct {
+	source string
+	root   node
+	names  []string // Referenced variables in order of first use, without builtins
+	tick   bool     // Whether the builtin tick is referenced
+}
+
+// Env holds the variable values for one evaluation.
+type Env struct {
+	Values []float64 // One per Names entry
+	Tick   float64
+}
+
+// String returns the expression source.
+func (e *Expression) String() string {
+	return e.source
+}
+
+// Names returns the referenced variables in order of first use.
+// Builtins such as tick are not included.
+func (e *Expression) Names() []string {
+	return slices.Clone(e.names)
+}
+
+// UsesTick reports whether the expression references the builtin tick.
+func (e *Expression) UsesTick() bool {
+	return e.tick
+}
+
+// Eval evaluates the expression.
+// Booleans are 1 (true) and 0 (false), any non-zero value is true.
+func (e *Expression) Eval(env Env) float64 {
+	return e.root.eval(&env)
+}
+
+// node is an element of the expression tree.
+type node interface {
+	eval(env *Env) float64
+}
+
+type number float64
+
+func (n number) eval(env *Env) float64 {
+	return float64(n)
+}
+
+// variable reads Env.Values by index.
+type variable int
+
+func (v variable) eval(env *Env) float64 {
+	return env.Values[v]
+}
+
+type tick struct{}
+
+func (tick) eval(env *Env) float64 {
+	return env.Tick
+}
+
+type unary struct {
+	op      string
+	operand node
+}
+
+func (u unary) eval(env *Env) float64 {
+	v := u.operand.eval(env)
+	switch u.op {
+	case "-":
+		return -v
+	case "!":
+		return boolean(v == 0)
+	default:
+		return v
+	}
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b binary) eval(env *Env) float64 {
+	// Logical operators short-circuit
+	switch b.op {
+	case "&&":
+		return boolean(b.left.eval(env) != 0 && b.right.eval(env) != 0)
+	case "||":
+		return boolean(b.left.eval(env) != 0 || b.right.eval(env) != 0)
+	}
+
+	l, r := b.left.eval(env), b.right.eval(env)
+	switch b.op {
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	case "%":
+		return math.Mod(l, r)
+	case "<":
+		return boolean(l < r)
+	case "<=":
+		return boolean(l <= r)
+	case ">":
+		return boolean(l > r)
+	case ">=":
+		return boolean(l >= r)
+	case "==":
+		return boolean(l == r)
+	case "!=":
+		return boolean(l != r)
+	default:
+		return math.NaN()
+	}
+}
+
+type conditional struct {
+	cond, then, otherwise node
+}
+
+func (c conditional) eval(env *Env) float64 {
+	if c.cond.eval(env) != 0 {
+		return c.then.eval(env)
+	}
+	return c.otherwise.eval(env)
+}
+
+type call struct {
+	fn   function
+	args []node
+}
+
+func (c call) eval(env *Env) float64 {
+	args := make([]float64, len(c.args))
+	for i, arg := range c.args {
+		args[i] = arg.eval(env)
+	}
+	return c.fn.apply(args)
+}
+
+// function is a builtin function with a fixed or minimum argument count.
+type function struct {
+	minArgs  int
+	variadic bool
+	apply    func(args []float64) float64
+}
+
+// functions are the builtin functions.
+var functions = map[string]function{
+	"abs":   {minArgs: 1, apply: func(a []float64) float64 { return math.Abs(a[0]) }},
+	"floor": {minArgs: 1, apply: func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {minArgs: 1, apply: func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {minArgs: 1, apply: func(a []float64) float64 { return math.Round(a[0]) }},
+	"sqrt":  {minArgs: 1, apply: func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"pow":   {minArgs: 2, apply: func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"clamp": {minArgs: 3, apply: func(a []float64) float64 { return max(a[1], min(a[0], a[2])) }},
+	"min":   {minArgs: 1, variadic: true, apply: func(a []float64) float64 { return slices.Min(a) }},
+	"max":   {minArgs: 1, variadic: true, apply: func(a []float64) float64 { return slices.Max(a) }},
+}
+
+func boolean(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,342 @@
+package expr
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth limits nesting so deeply nested input cannot exhaust the stack.
+const maxDepth = 64
+
+// Parse parses an expression.
+//
+// Grammar, lowest precedence first:
+//
+//	cond ? a : b
+//	||
+//	&&
+//	== !=
+//	< <= > >=
+//	+ -
+//	* / %
+//	unary - + !
+//	number, variable, tick, function(args...), (expression)
+func Parse(source string) (*Expression, error) {
+	tokens, err := tokenize(source)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	root, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, tok.errorf("unexpected %s", tok)
+	}
+
+	return &Expression{source: source, root: root, names: p.names, tick: p.tick}, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // Byte offset, 1-based in messages
+}
+
+func (t token) String() string {
+	if t.kind == tokenEOF {
+		return "end of expression"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+func (t token) errorf(format string, args ...any) error {
+	return fmt.Errorf("position %d: %s", t.pos+1, fmt.Sprintf(format, args...))
+}
+
+// operators lists operator tokens, longer operators first.
+var operators = []string{
+	"&&", "||", "==", "!=", "<=", ">=",
+	"+", "-", "*", "/", "%", "<", ">", "!", "?", ":", "(", ")", ",",
+}
+
+// tokenize splits the source into tokens.
+func tokenize(source string) ([]token, error) {
+	var tokens []token
+	pos := 0
+
+	for pos < len(source) {
+		c := source[pos]
+
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			pos++
+
+		case isDigit(c) || c == '.':
+			end := pos
+			for end < len(source) && (isDigit(source[end]) || source[end] == '.') {
+				end++
+			}
+			// Exponent, e.g. 1e6 or 2.5E-3
+			if end < len(source) && (source[end] == 'e' || source[end] == 'E') {
+				exp := end + 1
+				if exp < len(source) && (source[exp] == '+' || source[exp] == '-') {
+					exp++
+				}
+				if exp < len(source) && isDigit(source[exp]) {
+					end = exp
+					for end < len(source) && isDigit(source[end]) {
+						end++
+					}
+				}
+			}
+			text := source[pos:end]
+			if _, err := strconv.ParseFloat(text, 64); err != nil {
+				return nil, token{pos: pos}.errorf("invalid number %q", text)
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, pos: pos})
+			pos = end
+
+		case c == '_' || isLetter(c):
+			end := pos
+			for end < len(source) && (source[end] == '_' || isLetter(source[end]) || isDigit(source[end])) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: source[pos:end], pos: pos})
+			pos = end
+
+		default:
+			i := slices.IndexFunc(operators, func(op string) bool {
+				return strings.HasPrefix(source[pos:], op)
+			})
+			if i < 0 {
+				r, _ := utf8.DecodeRuneInString(source[pos:])
+				return nil, token{pos: pos}.errorf("unexpected character %q", r)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: operators[i], pos: pos})
+			pos += len(operators[i])
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(source)}), nil
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// parser is a recursive descent parser over tokens.
+type parser struct {
+	tokens []token
+	pos    int
+	depth  int
+	names  []string
+	tick   bool
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token if it is one of the given operators.
+func (p *parser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind == tokenOperator && slices.Contains(ops, tok.text) {
+		p.pos++
+		return tok.text, true
+	}
+	return "", false
+}
+
+func (p *parser) expect(op string) error {
+	if _, ok := p.accept(op); !ok {
+		tok := p.peek()
+		return tok.errorf("expected %q, got %s", op, tok)
+	}
+	return nil
+}
+
+// conditional parses cond ? a : b, right-associative.
+func (p *parser) conditional() (node, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, p.peek().errorf("expression nested too deeply")
+	}
+
+	cond, err := p.binary(0)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := p.accept("?"); !ok {
+		return cond, nil
+	}
+
+	then, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(":"); err != nil {
+		return nil, err
+	}
+	otherwise, err := p.conditional()
+	if err != nil {
+		return nil, err
+	}
+
+	return conditional{cond: cond, then: then, otherwise: otherwise}, nil
+}
+
+// precedence lists binary operators from lowest to highest precedence.
+var precedence = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<", "<=", ">", ">="},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+// binary parses left-associative binary operators of the given level and above.
+func (p *parser) binary(level int) (node, error) {
+	if level == len(precedence) {
+		return p.unary()
+	}
+
+	left, err := p.binary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept(precedence[level]...)
+		if !ok {
+			return left, nil
+		}
+		right, err := p.binary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *parser) unary() (node, error) {
+	op, ok := p.accept("-", "+", "!")
+	if !ok {
+		return p.primary()
+	}
+
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return nil, p.peek().errorf("expression nested too deeply")
+	}
+
+	operand, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return unary{op: op, operand: operand}, nil
+}
+
+func (p *parser) primary() (node, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokenNumber:
+		v, _ := strconv.ParseFloat(tok.text, 64) // Validated by tokenize
+		return number(v), nil
+
+	case tokenIdent:
+		if _, ok := p.accept("("); ok {
+			return p.call(tok)
+		}
+		if tok.text == TickName {
+			p.tick = true
+			return tick{}, nil
+		}
+		if _, ok := functions[tok.text]; ok {
+			return nil, tok.errorf("function %s requires arguments", tok.text)
+		}
+		idx := slices.Index(p.names, tok.text)
+		if idx < 0 {
+			idx = len(p.names)
+			p.names = append(p.names, tok.text)
+		}
+		return variable(idx), nil
+
+	case tokenOperator:
+		if tok.text == "(" {
+			inner, err := p.conditional()
+			if err != nil {
+				return nil, err
+			}
+			if err := p.expect(")"); err != nil {
+				return nil, err
+			}
+			return inner, nil
+		}
+	}
+
+	return nil, tok.errorf("unexpected %s", tok)
+}
+
+// call parses the arguments of a function call, the opening parenthesis is consumed.
+func (p *parser) call(name token) (node, error) {
+	fn, ok := functions[name.text]
+	if !ok {
+		return nil, name.errorf("unknown function %s", name.text)
+	}
+
+	var args []node
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.conditional()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if _, ok := p.accept(","); !ok {
+				break
+			}
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+	}
+
+	switch {
+	case fn.variadic && len(args) < fn.minArgs:
+		return nil, name.errorf("function %s requires at least %d arguments, got %d", name.text, fn.minArgs, len(args))
+	case !fn.variadic && len(args) != fn.minArgs:
+		return nil, name.errorf("function %s requires %d arguments, got %d", name.text, fn.minArgs, len(args))
+	}
+
+	return call{fn: fn, args: args}, nil
+}
@@ -10,19 +10,21 @@ import (
 	"github.com/neox5/otelbox/internal/simulation"
 	"github.com/neox5/simv/clock"
 	"github.com/neox5/simv/source"
+	"github.com/neox5/simv/value"
 )
 
 // Generator manages simv components and value generation.
 type Generator struct {
 	// Lifecycle management - unique objects only
 	clocks  []clock.Clock
-	sources []source.Publisher[int]
+	sources []source.Publisher[float64]
 	values  []*simulation.ValueWrapper
 
 	// Instance sharing - named references
 	clockInstances  map[string]clock.Clock
-	sourceInstances map[string]source.Publisher[int]
+	sourceInstances map[string]source.Publisher[float64]
 	valueInstances  map[string]*simulation.ValueWrapper
+	restartClocks   map[string]clock.Clock // By clock instance and probability
 
 	// Metric indexing - fast lookup by metric index
 	metricValues []*simulation.ValueWrapper
@@ -34,30 +36,22 @@ type Generator struct {
 func New(metrics []config.MetricConfig) (*Generator, error) {
 	g := &Generator{
 		clockInstances:  make(map[string]clock.Clock),
-		sourceInstances: make(map[string]source.Publisher[int]),
+		sourceInstances: make(map[string]source.Publisher[float64]),
 		valueInstances:  make(map[string]*simulation.ValueWrapper),
+		restartClocks:   make(map[string]clock.Clock),
 		metricValues:    make([]*simulation.ValueWrapper, len(metrics)),
 	}
 
 	for i, metric := range metrics {
-		// Get or create clock
-		clk, err := g.getOrCreateClock(metric.Value.Source)
-		if err != nil {
-			return nil, fmt.Errorf("metric %d (%s): failed to create clock: %w",
-				i, metric.PrometheusName, err)
-		}
-
-		// Get or create source
-		src, err := g.getOrCreateSource(metric.Value, clk)
-		if err != nil {
-			return nil, fmt.Errorf("metric %d (%s): failed to create source: %w",
-				i, metric.PrometheusName, err)
+		// Info metrics are constant - no clock, source or value
+		if metric.Type == config.MetricTypeInfo {
+			continue
 		}
 
-		// Get or create value
-		val, err := g.getOrCreateValue(metric.Value, src)
+		// Get or create value (with its source and clock)
+		val, err := g.getOrCreateValue(metric.Value)
 		if err != nil {
-			return nil, fmt.Errorf("metric %d (%s): failed to create value: %w",
+			return nil, fmt.Errorf("metric %d (%s): %w",
 				i, metric.PrometheusName, err)
 		}
 
@@ -88,12 +82,90 @@ func New(metrics []config.MetricConfig) (*Generator, error) {
 	return g, nil
 }
 
-// getOrCreateClock returns cached clock if ClockRef is set, otherwise creates new.
+// getOrCreatePublisher returns the publisher feeding a value.
+// Creates clock and source, or the source combining derive or expr inputs.
+func (g *Generator) getOrCreatePublisher(valueCfg config.ValueConfig) (source.Publisher[float64], error) {
+	if valueCfg.Derive != nil {
+		inputs, err := g.getOrCreateInputs(valueCfg.Derive.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create derived source: %w", err)
+		}
+		src, err := simulation.CreateDerivedSource(*valueCfg.Derive, inputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create derived source: %w", err)
+		}
+
+		// Add to lifecycle management
+		g.sources = append(g.sources, src)
+
+		slog.Debug("created derived source",
+			"derive", valueCfg.Derive.String())
+
+		return src, nil
+	}
+
+	if valueCfg.Expr != nil {
+		inputs, err := g.getOrCreateInputs(valueCfg.Expr.Inputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expr source: %w", err)
+		}
+		src, err := simulation.CreateExprSource(*valueCfg.Expr, inputs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create expr source: %w", err)
+		}
+
+		// Add to lifecycle management
+		g.sources = append(g.sources, src)
+
+		slog.Debug("created expr source",
+			"expr", valueCfg.Expr.Expression.String())
+
+		return src, nil
+	}
+
+	// Get or create clock
+	clk, err := g.getOrCreateClock(valueCfg.Source.Clock, valueCfg.Source.ClockRef)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create clock: %w", err)
+	}
+
+	// Get or create source
+	src, err := g.getOrCreateSource(valueCfg, clk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create source: %w", err)
+	}
+
+	return src, nil
+}
+
+// getOrCreateInputs returns the publishers of derive or expr inputs.
+// Inputs are shared by name, so derived values see the same updates as
+// metrics using the same instances.
+func (g *Generator) getOrCreateInputs(inputCfgs []config.DeriveInput) ([]value.Publisher[float64], error) {
+	inputs := make([]value.Publisher[float64], len(inputCfgs))
+	for i, input := range inputCfgs {
+		var err error
+		if input.Source != nil {
+			inputs[i], err = g.getOrCreatePublisher(config.ValueConfig{
+				Source:    *input.Source,
+				SourceRef: &input.Name,
+			})
+		} else {
+			inputs[i], err = g.getOrCreateValue(*input.Value)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("input %q: %w", input.Name, err)
+		}
+	}
+	return inputs, nil
+}
+
+// getOrCreateClock returns cached clock if clockRef is set, otherwise creates new.
 // Adds unique clocks to lifecycle management.
-func (g *Generator) getOrCreateClock(sourceCfg config.SourceConfig) (clock.Clock, error) {
+func (g *Generator) getOrCreateClock(clockCfg config.ClockConfig, clockRef *string) (clock.Clock, error) {
 	// Check if clock is shared instance
-	if sourceCfg.ClockRef != nil {
-		instanceName := *sourceCfg.ClockRef
+	if clockRef != nil {
+		instanceName := *clockRef
 
 		// Return cached clock if already created
 		if clk, exists := g.clockInstances[instanceName]; exists {
@@ -101,7 +173,7 @@ func (g *Generator) getOrCreateClock(sourceCfg config.SourceConfig) (clock.Clock
 		}
 
 		// Create new clock
-		clk, err := simulation.CreateClock(sourceCfg.Clock)
+		clk, err := simulation.CreateClock(clockCfg)
 		if err != nil {
 			return nil, fmt.Errorf("clock instance %q: %w", instanceName, err)
 		}
@@ -115,15 +187,13 @@ func (g *Generator) getOrCreateClock(sourceCfg config.SourceConfig) (clock.Clock
 		// Log clock creation
 		slog.Debug("created clock",
 			"name", instanceName,
-			slog.Group("clock",
-				"type", sourceCfg.Clock.Type,
-				"interval", sourceCfg.Clock.Interval))
+			"clock", clockCfg)
 
 		return clk, nil
 	}
 
 	// Unique clock - create new without caching
-	clk, err := simulation.CreateClock(sourceCfg.Clock)
+	clk, err := simulation.CreateClock(clockCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -134,16 +204,14 @@ func (g *Generator) getOrCreateClock(sourceCfg config.SourceConfig) (clock.Clock
 	// Log clock creation
 	slog.Debug("created clock",
 		"name", "<inline>",
-		slog.Group("clock",
-			"type", sourceCfg.Clock.Type,
-			"interval", sourceCfg.Clock.Interval))
+		"clock", clockCfg)
 
 	return clk, nil
 }
 
 // getOrCreateSource returns cached source if SourceRef is set, otherwise creates new.
 // Adds unique sources to lifecycle management.
-func (g *Generator) getOrCreateSource(valueCfg config.ValueConfig, clk clock.Clock) (source.Publisher[int], error) {
+func (g *Generator) getOrCreateSource(valueCfg config.ValueConfig, clk clock.Clock) (source.Publisher[float64], error) {
 	// Check if source is shared instance
 	if valueCfg.SourceRef != nil {
 		instanceName := *valueCfg.SourceRef
@@ -166,17 +234,9 @@ func (g *Generator) getOrCreateSource(valueCfg config.ValueConfig, clk clock.Clo
 		g.sources = append(g.sources, src)
 
 		// Log source creation
-		clockName := "<inline>"
-		if valueCfg.Source.ClockRef != nil {
-			clockName = "instance:" + *valueCfg.Source.ClockRef
-		}
 		slog.Debug("created source",
 			"name", instanceName,
-			slog.Group("source",
-				"type", valueCfg.Source.Type,
-				"clock", clockName,
-				"min", valueCfg.Source.Min,
-				"max", valueCfg.Source.Max))
+			"source", valueCfg.Source)
 
 		return src, nil
 	}
@@ -191,33 +251,70 @@ func (g *Generator) getOrCreateSource(valueCfg config.ValueConfig, clk clock.Clo
 	g.sources = append(g.sources, src)
 
 	// Log source creation
-	clockName := "<inline>"
-	if valueCfg.Source.ClockRef != nil {
-		clockName = "instance:" + *valueCfg.Source.ClockRef
-	}
 	slog.Debug("created source",
 		"name", "<inline>",
-		slog.Group("source",
-			"type", valueCfg.Source.Type,
-			"clock", clockName,
-			"min", valueCfg.Source.Min,
-			"max", valueCfg.Source.Max))
+		"source", valueCfg.Source)
 
 	return src, nil
 }
 
-// getOrCreateValue creates or returns cached value.
-// Values are always added to lifecycle management.
-func (g *Generator) getOrCreateValue(valueCfg config.ValueConfig, src source.Publisher[int]) (*simulation.ValueWrapper, error) {
-	// Note: Value instance sharing not yet implemented in config
-	// This structure supports future value instance sharing
+// getOrCreateValue returns cached value if ValueRef is set, otherwise creates new.
+// Adds unique values to lifecycle management.
+func (g *Generator) getOrCreateValue(valueCfg config.ValueConfig) (*simulation.ValueWrapper, error) {
+	// Check if value is shared instance
+	if valueCfg.ValueRef != nil {
+		instanceName := *valueCfg.ValueRef
 
-	// Create value
-	val, err := simulation.CreateValue(valueCfg, src)
+		// Return cached value if already created
+		if val, exists := g.valueInstances[instanceName]; exists {
+			return val, nil
+		}
+
+		// Create new value
+		val, err := g.createValue(instanceName, valueCfg)
+		if err != nil {
+			return nil, fmt.Errorf("value instance %q: %w", instanceName, err)
+		}
+
+		// Cache for sharing
+		g.valueInstances[instanceName] = val
+
+		return val, nil
+	}
+
+	// Unique value - create new without caching
+	return g.createValue("<inline>", valueCfg)
+}
+
+// createValue creates a value with its publisher and adds it to lifecycle management.
+func (g *Generator) createValue(name string, valueCfg config.ValueConfig) (*simulation.ValueWrapper, error) {
+	// Get or create source (with its clock), derived or expr source
+	src, err := g.getOrCreatePublisher(valueCfg)
 	if err != nil {
 		return nil, err
 	}
 
+	// Get or create the clock driving on_interval and on_tick resets or restarts
+	var resetClock clock.Clock
+	switch {
+	case valueCfg.Reset.OnClock():
+		resetClock, err = g.getOrCreateClock(valueCfg.Reset.Clock, valueCfg.Reset.ClockRef)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create reset clock: %w", err)
+		}
+	case valueCfg.Restart != nil:
+		resetClock, err = g.getOrCreateRestartClock(*valueCfg.Restart)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create restart clock: %w", err)
+		}
+	}
+
+	// Create value
+	val, err := simulation.CreateValue(valueCfg, src, resetClock)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create value: %w", err)
+	}
+
 	// Add to lifecycle management
 	g.values = append(g.values, val)
 
@@ -226,30 +323,39 @@ func (g *Generator) getOrCreateValue(valueCfg config.ValueConfig, src source.Pub
 	if valueCfg.SourceRef != nil {
 		sourceName = "instance:" + *valueCfg.SourceRef
 	}
+	if valueCfg.Derive != nil {
+		sourceName = "derive:" + valueCfg.Derive.String()
+	}
+	if valueCfg.Expr != nil {
+		sourceName = "expr:" + valueCfg.Expr.Expression.String()
+	}
 
 	transformNames := make([]string, len(valueCfg.Transforms))
 	for i, t := range valueCfg.Transforms {
-		transformNames[i] = t.Type
+		transformNames[i] = t.String()
 	}
 
 	attrs := []any{
-		"name", "<inline>",
+		"name", name,
 		slog.Group("value",
 			"source", sourceName,
 			"transforms", fmt.Sprintf("[%s]", strings.Join(transformNames, " "))),
 	}
-	if valueCfg.Reset.Type != "" {
-		resetDesc := valueCfg.Reset.Type
-		if valueCfg.Reset.Value != 0 {
-			resetDesc = fmt.Sprintf("%s:%d", valueCfg.Reset.Type, valueCfg.Reset.Value)
+	if valueCfg.Reset.Type != "" || valueCfg.Restart != nil {
+		// Add reset and restart to the value group
+		valueAttrs := []any{
+			"source", sourceName,
+			"transforms", fmt.Sprintf("[%s]", strings.Join(transformNames, " ")),
+		}
+		if valueCfg.Reset.Type != "" {
+			valueAttrs = append(valueAttrs, "reset", valueCfg.Reset.String())
+		}
+		if valueCfg.Restart != nil {
+			valueAttrs = append(valueAttrs, "restart", valueCfg.Restart.String())
 		}
-		// Add reset to the value group
 		attrs = []any{
-			"name", "<inline>",
-			slog.Group("value",
-				"source", sourceName,
-				"transforms", fmt.Sprintf("[%s]", strings.Join(transformNames, " ")),
-				"reset", resetDesc),
+			"name", name,
+			slog.Group("value", valueAttrs...),
 		}
 	}
 
@@ -258,16 +364,53 @@ func (g *Generator) getOrCreateValue(valueCfg config.ValueConfig, src source.Pub
 	return val, nil
 }
 
+// getOrCreateRestartClock returns the cached restart clock if the restart
+// clock is a shared instance, otherwise creates new. Values sharing a restart
+// clock restart together.
+func (g *Generator) getOrCreateRestartClock(restartCfg config.RestartConfig) (clock.Clock, error) {
+	clk, err := g.getOrCreateClock(restartCfg.Clock, restartCfg.ClockRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unique clock - create new without caching
+	if restartCfg.ClockRef == nil {
+		return simulation.CreateRestartClock(clk, restartCfg.Probability), nil
+	}
+
+	key := fmt.Sprintf("%s:%g", *restartCfg.ClockRef, restartCfg.Probability)
+	if restartClock, exists := g.restartClocks[key]; exists {
+		return restartClock, nil
+	}
+
+	restartClock := simulation.CreateRestartClock(clk, restartCfg.Probability)
+	g.restartClocks[key] = restartClock
+
+	return restartClock, nil
+}
+
 // Start begins value generation by starting all unique clocks.
 func (g *Generator) Start() {
 	// Start each unique clock exactly once
 	for _, clk := range g.clocks {
 		clk.Start()
 	}
+
+	// Advance virtual time once all clocks scheduled their ticks
+	simulation.RunTimeline()
+}
+
+// Done returns a channel closed once virtual time reached its end.
+// Returns nil if the simulation runs until stopped.
+func (g *Generator) Done() <-chan struct{} {
+	return simulation.TimelineDone()
 }
 
 // Stop halts value generation and releases resources.
 func (g *Generator) Stop() {
+	// Stop virtual time before its clocks
+	simulation.StopTimeline()
+
 	// Stop unique clocks
 	for _, clk := range g.clocks {
 		clk.Stop()
@@ -280,6 +423,7 @@ func (g *Generator) Stop() {
 }
 
 // GetValue returns the value at the specified metric index.
+// Returns nil for metrics without value (info).
 func (g *Generator) GetValue(index int) *simulation.ValueWrapper {
 	if index < 0 || index >= len(g.metricValues) {
 		return nil
@@ -1,13 +1,30 @@
 package metric
 
-import "github.com/neox5/simv/value"
+import (
+	"time"
+
+	"github.com/neox5/otelbox/internal/simulation"
+)
 
 // MetricType defines the semantic type of a metric.
 type MetricType string
 
 const (
-	MetricTypeCounter MetricType = "counter"
-	MetricTypeGauge   MetricType = "gauge"
+	MetricTypeCounter       MetricType = "counter"
+	MetricTypeGauge         MetricType = "gauge"
+	MetricTypeUpDownCounter MetricType = "updowncounter"
+	MetricTypeHistogram     MetricType = "histogram"
+	MetricTypeSummary       MetricType = "summary"
+	MetricTypeInfo          MetricType = "info"
+	MetricTypeStateSet      MetricType = "stateset"
+)
+
+// ValueType defines the numeric representation of exported values.
+type ValueType string
+
+const (
+	ValueTypeInt   ValueType = "int"
+	ValueTypeFloat ValueType = "float"
 )
 
 // Descriptor holds protocol-agnostic metric metadata and value reference.
@@ -15,7 +32,34 @@ type Descriptor struct {
 	PrometheusName string
 	OTELName       string
 	Type           MetricType
+	ValueType      ValueType
 	Description    string
 	Attributes     map[string]string
-	Value          *value.Value[int]
+	Value          Reader     // Set by Registry.ReadMetrics, nil for info metrics
+	Updates        Observable // Every value update, used by histograms
+	Lifetime       Lifetime   // Start of cumulative series, nil for info metrics
+	Restarts       bool       // Value restarts, exporters signal each new lifetime
+
+	// Aggregated observations - at most one is set, depending on metric type
+	Histogram            *simulation.Histogram
+	ExponentialHistogram *simulation.ExponentialHistogram
+	Summary              *simulation.Summary
+	StateSet             *simulation.StateSet
+	StateLabel           string // Attribute holding the state name of a stateset
+}
+
+// Reader reads the current value of a metric for one exporter.
+// Depending on the metric's read mode, reading resets the exporter's window (reset on read).
+type Reader interface {
+	Value() float64
+}
+
+// Lifetime reports when a value was created or last restarted.
+type Lifetime interface {
+	Created() time.Time
+}
+
+// Observable delivers every value update to registered observers.
+type Observable interface {
+	AddObserver(observer func(float64))
 }
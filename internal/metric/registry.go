@@ -2,41 +2,127 @@ package metric
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/neox5/otelbox/internal/config"
 	"github.com/neox5/otelbox/internal/generator"
+	"github.com/neox5/otelbox/internal/simulation"
 )
 
 // Registry holds protocol-agnostic metric definitions.
 type Registry struct {
 	metrics []Descriptor
+	reads   []valueRead // Per metric, read through cursors by ReadMetrics
+}
+
+// valueRead describes how exporters read the value of a metric.
+type valueRead struct {
+	value *simulation.ValueWrapper // Nil for info metrics
+	peek  bool
 }
 
 // New creates a registry from configuration.
 func New(cfg *config.Config, gen *generator.Generator) (*Registry, error) {
 	var metrics []Descriptor
+	var reads []valueRead
 
 	for i, metricCfg := range cfg.Metrics {
+		desc := Descriptor{
+			PrometheusName: metricCfg.PrometheusName,
+			OTELName:       metricCfg.OTELName,
+			Type:           MetricType(metricCfg.Type),
+			ValueType:      ValueType(metricCfg.ValueType),
+			Description:    metricCfg.Description,
+			Attributes:     metricCfg.Attributes,
+		}
+
+		// Info metrics are constant, there is no value to read
+		if metricCfg.Type == config.MetricTypeInfo {
+			metrics = append(metrics, desc)
+			reads = append(reads, valueRead{})
+			continue
+		}
+
 		val := gen.GetValue(i)
 		if val == nil {
 			return nil, fmt.Errorf("metric %d (%s): value not found",
 				i, metricCfg.PrometheusName)
 		}
+		desc.Updates = val
+		desc.Lifetime = val
+		desc.Restarts = metricCfg.Value.Restart != nil
 
-		metrics = append(metrics, Descriptor{
-			PrometheusName: metricCfg.PrometheusName,
-			OTELName:       metricCfg.OTELName,
-			Type:           MetricType(metricCfg.Type),
-			Description:    metricCfg.Description,
-			Attributes:     metricCfg.Attributes,
-			Value:          val.Value,
-		})
+		// Histograms, summaries and statesets process every value update
+		switch metricCfg.Type {
+		case config.MetricTypeHistogram:
+			switch metricCfg.Histogram.Mode {
+			case config.HistogramModeExponential:
+				desc.ExponentialHistogram = simulation.NewExponentialHistogram(
+					metricCfg.Histogram.Scale,
+					metricCfg.Histogram.MaxBuckets,
+				)
+				val.AddObserver(desc.ExponentialHistogram.Observe)
+			default:
+				desc.Histogram = simulation.NewHistogram(metricCfg.Histogram.Buckets)
+				val.AddObserver(desc.Histogram.Observe)
+			}
+		case config.MetricTypeSummary:
+			desc.Summary = simulation.NewSummary(metricCfg.Summary.Quantiles, metricCfg.Summary.Window)
+			val.AddObserver(desc.Summary.Observe)
+		case config.MetricTypeStateSet:
+			desc.StateSet = simulation.NewStateSet(
+				metricCfg.StateSet.States,
+				metricCfg.StateSet.Initial,
+				metricCfg.StateSet.Transitions,
+			)
+			desc.StateLabel = metricCfg.StateSet.Label
+			val.AddObserver(desc.StateSet.Observe)
+		}
+
+		metrics = append(metrics, desc)
+		reads = append(reads, valueRead{value: val, peek: metricCfg.Read == config.ReadModePeek})
 	}
 
-	return &Registry{metrics: metrics}, nil
+	return &Registry{metrics: metrics, reads: reads}, nil
 }
 
-// Metrics returns all registered metric descriptors.
+// Metrics returns all registered metric descriptors, without value readers.
 func (r *Registry) Metrics() []Descriptor {
 	return r.metrics
 }
+
+// ReadMetrics returns all metric descriptors with values read through new cursors.
+// Each exporter reads through cursors of its own: reset on read only resets
+// the windows of that exporter, so exporters read values independently.
+// Metrics sharing a value instance share its cursor.
+func (r *Registry) ReadMetrics() []Descriptor {
+	metrics := slices.Clone(r.metrics)
+	cursors := make(map[*simulation.ValueWrapper]*simulation.Cursor)
+
+	for i, read := range r.reads {
+		if read.value == nil {
+			continue
+		}
+		cursor, ok := cursors[read.value]
+		if !ok {
+			cursor = read.value.NewCursor()
+			cursors[read.value] = cursor
+		}
+		metrics[i].Value = cursor
+		if read.peek {
+			metrics[i].Value = peekReader{cursor}
+		}
+	}
+
+	return metrics
+}
+
+// peekReader reads a value without resetting the cursor.
+type peekReader struct {
+	*simulation.Cursor
+}
+
+// Value implements Reader.
+func (r peekReader) Value() float64 {
+	return r.Peek()
+}
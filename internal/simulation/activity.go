@@ -0,0 +1,37 @@
+package simulation
+
+import "sync"
+
+// activity counts ticks and values in flight between simulation stages.
+// At maximum speed the timeline waits for the simulation to settle before
+// advancing virtual time, so every update is processed at the virtual time
+// of its tick. Senders add one per receiver before sending, receivers call
+// done once they processed the message, including everything it triggered.
+// Tracking is disabled in real time and at scaled speed.
+var activity tracker
+
+type tracker struct {
+	enabled bool // Set by InitializeTime, before any clock is created
+	wg      sync.WaitGroup
+}
+
+// add registers n messages about to be sent.
+func (t *tracker) add(n int) {
+	if t.enabled {
+		t.wg.Add(n)
+	}
+}
+
+// done marks a received message as processed.
+func (t *tracker) done() {
+	if t.enabled {
+		t.wg.Done()
+	}
+}
+
+// wait blocks until all messages are processed.
+func (t *tracker) wait() {
+	if t.enabled {
+		t.wg.Wait()
+	}
+}
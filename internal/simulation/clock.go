@@ -2,8 +2,10 @@ package simulation
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/otelbox/internal/cron"
 	"github.com/neox5/simv/clock"
 )
 
@@ -11,8 +13,72 @@ import (
 func CreateClock(cfg config.ClockConfig) (clock.Clock, error) {
 	switch cfg.Type {
 	case "periodic":
-		return clock.NewPeriodicClock(cfg.Interval), nil
+		if timeline != nil {
+			// simv clocks tick in real time
+			return newBroadcastClock(newFixedClock(cfg.Interval)), nil
+		}
+		return newBroadcastClock(clock.NewPeriodicClock(cfg.Interval)), nil
+	case "jitter":
+		return newBroadcastClock(newJitterClock(cfg.Interval, cfg.Jitter)), nil
+	case "poisson":
+		return newBroadcastClock(newPoissonClock(cfg.Rate)), nil
+	case "cron":
+		schedule, err := cron.Parse(cfg.Schedule)
+		if err != nil {
+			return nil, fmt.Errorf("cron clock: %w", err)
+		}
+		return newBroadcastClock(newCronClock(schedule, cfg.Location())), nil
 	default:
 		return nil, fmt.Errorf("unknown clock type: %s", cfg.Type)
 	}
 }
+
+// broadcastClock delivers every tick to all subscribers.
+// simv clocks return one shared channel from Subscribe, so subscribers of a
+// shared clock instance would compete for ticks instead of all receiving them.
+type broadcastClock struct {
+	clock.Clock
+
+	initOnce    sync.Once
+	mu          sync.Mutex
+	subscribers []chan struct{}
+}
+
+func newBroadcastClock(clk clock.Clock) *broadcastClock {
+	return &broadcastClock{Clock: clk}
+}
+
+// Subscribe returns a channel receiving every tick.
+func (c *broadcastClock) Subscribe() <-chan struct{} {
+	c.initOnce.Do(func() {
+		go c.run(c.Clock.Subscribe())
+	})
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ch := make(chan struct{})
+	c.subscribers = append(c.subscribers, ch)
+	return ch
+}
+
+// run fans out ticks until the clock stops, then closes subscriber channels.
+func (c *broadcastClock) run(ticks <-chan struct{}) {
+	for range ticks {
+		c.mu.Lock()
+		subs := c.subscribers
+		c.mu.Unlock()
+
+		activity.add(len(subs))
+		for _, subChan := range subs {
+			subChan <- struct{}{}
+		}
+		activity.done()
+	}
+
+	c.mu.Lock()
+	for _, subChan := range c.subscribers {
+		close(subChan)
+	}
+	c.mu.Unlock()
+}
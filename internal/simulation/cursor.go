@@ -0,0 +1,68 @@
+package simulation
+
+import "sync"
+
+// Cursor reads a value on behalf of one exporter.
+// Reads never reset the value itself: a value resetting on read resets the
+// cursor instead, so every exporter reads its own windows. The cursor replays
+// each update as if only its reads reset the value, and reports what a
+// single exporter would read.
+type Cursor struct {
+	value *ValueWrapper
+	reads *readReset // Nil unless the value resets on read
+
+	mu      sync.Mutex
+	current float64
+}
+
+// readReset holds the reset on read settings of a value.
+type readReset struct {
+	value        float64
+	accumulating bool // Last transform accumulates from the value state
+}
+
+// NewCursor creates a cursor starting at the current value.
+// Exporters create their cursors before generation starts.
+func (w *ValueWrapper) NewCursor() *Cursor {
+	c := &Cursor{value: w, reads: w.reads, current: w.Peek()}
+	if c.reads != nil {
+		w.updates.addCursor(c)
+	}
+	return c
+}
+
+// Value returns the current value, resetting the cursor if the value resets on read.
+func (c *Cursor) Value() float64 {
+	if c.reads == nil {
+		return c.value.Peek()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	current := c.current
+	c.current = c.reads.value
+	return current
+}
+
+// Peek returns the current value without resetting the cursor.
+func (c *Cursor) Peek() float64 {
+	if c.reads == nil {
+		return c.value.Peek()
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current
+}
+
+// update applies a value update. An accumulating value adds the input of
+// its last transform to the cursor state, like it does to its own state.
+func (c *Cursor) update(input, finalState float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reads.accumulating {
+		c.current += input
+	} else {
+		c.current = finalState
+	}
+}
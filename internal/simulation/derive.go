@@ -0,0 +1,239 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/otelbox/internal/expr"
+	"github.com/neox5/simv/source"
+	"github.com/neox5/simv/value"
+)
+
+// derivedSource combines the updates of several inputs into one value.
+// Inputs driven by one clock instance are paired tick by tick: updates are
+// queued per input and combined in order, so invariants between them hold
+// even if one input delivers the next tick before another delivered the
+// current one. Otherwise a value is emitted once every input delivered an
+// update since the last emission, combining the latest update of each input.
+// Inputs are received concurrently, a slow input never blocks the publisher
+// of another one.
+type derivedSource struct {
+	inputs  []value.Publisher[float64]
+	combine func(values []float64) float64
+
+	initOnce        sync.Once
+	mu              sync.Mutex
+	subscribers     []chan float64
+	generationCount atomic.Uint64
+
+	emitMu  sync.Mutex // Serializes updates of latest and emissions
+	latest  []float64
+	fresh   []bool
+	paired  bool        // Inputs share one clock, combined tick by tick
+	queues  [][]float64 // Unpaired updates per input
+	stopped bool        // An input stopped, no further tick can be paired
+}
+
+// CreateDerivedSource creates a source combining the given inputs.
+// Inputs must be ordered like cfg.Inputs.
+func CreateDerivedSource(cfg config.DeriveConfig, inputs []value.Publisher[float64]) (source.Publisher[float64], error) {
+	if len(inputs) != len(cfg.Inputs) {
+		return nil, fmt.Errorf("derive %s: expected %d inputs, got %d", cfg.Op, len(cfg.Inputs), len(inputs))
+	}
+
+	combine, err := deriveCombiner(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return newDerivedSource(inputs, combine, cfg.SharedClock()), nil
+}
+
+// CreateExprSource creates a source evaluating an expression over the inputs.
+// Inputs must be ordered like cfg.Inputs. The tick variable counts
+// evaluations, starting at 0.
+func CreateExprSource(cfg config.ExprConfig, inputs []value.Publisher[float64]) (source.Publisher[float64], error) {
+	if len(inputs) != len(cfg.Inputs) {
+		return nil, fmt.Errorf("expr %q: expected %d inputs, got %d", cfg.Expression, len(cfg.Inputs), len(inputs))
+	}
+
+	expression := cfg.Expression
+	var tick uint64
+
+	// Evaluations are serialized by derivedSource
+	return newDerivedSource(inputs, func(values []float64) float64 {
+		result := expression.Eval(expr.Env{Values: values, Tick: float64(tick)})
+		tick++
+		return result
+	}, cfg.SharedClock()), nil
+}
+
+func newDerivedSource(inputs []value.Publisher[float64], combine func([]float64) float64, paired bool) *derivedSource {
+	return &derivedSource{
+		inputs:  inputs,
+		combine: combine,
+		latest:  make([]float64, len(inputs)),
+		fresh:   make([]bool, len(inputs)),
+		paired:  paired,
+		queues:  make([][]float64, len(inputs)),
+	}
+}
+
+// deriveCombiner returns the function combining one value per input.
+func deriveCombiner(cfg config.DeriveConfig) (func([]float64) float64, error) {
+	switch cfg.Op {
+	case config.DeriveOpSum:
+		return func(values []float64) float64 {
+			var sum float64
+			for _, v := range values {
+				sum += v
+			}
+			return sum
+		}, nil
+	case config.DeriveOpDiff:
+		return func(values []float64) float64 {
+			return values[0] - values[1]
+		}, nil
+	case config.DeriveOpRatio:
+		// Division by zero yields +/-Inf or NaN
+		return func(values []float64) float64 {
+			return values[0] / values[1]
+		}, nil
+	case config.DeriveOpMin:
+		return func(values []float64) float64 {
+			result := values[0]
+			for _, v := range values[1:] {
+				result = min(result, v)
+			}
+			return result
+		}, nil
+	case config.DeriveOpMax:
+		return func(values []float64) float64 {
+			result := values[0]
+			for _, v := range values[1:] {
+				result = max(result, v)
+			}
+			return result
+		}, nil
+	case config.DeriveOpWeightedSum:
+		if len(cfg.Weights) != len(cfg.Inputs) {
+			return nil, fmt.Errorf("derive weighted_sum requires one weight per input")
+		}
+		weights := cfg.Weights
+		return func(values []float64) float64 {
+			var sum float64
+			for i, v := range values {
+				sum += weights[i] * v
+			}
+			return sum
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown derive op: %s", cfg.Op)
+	}
+}
+
+// Subscribe returns a channel receiving every combined value.
+// Inputs are subscribed lazily on first Subscribe.
+func (s *derivedSource) Subscribe() <-chan float64 {
+	s.initOnce.Do(func() {
+		var wg sync.WaitGroup
+		for i, input := range s.inputs {
+			ch := input.Subscribe()
+			wg.Go(func() { s.receive(i, ch) })
+		}
+
+		// All inputs stopped - close subscriber channels
+		go func() {
+			wg.Wait()
+			s.mu.Lock()
+			for _, subChan := range s.subscribers {
+				close(subChan)
+			}
+			s.mu.Unlock()
+		}()
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan float64)
+	s.subscribers = append(s.subscribers, ch)
+	return ch
+}
+
+// receive records updates of one input and emits once all inputs updated.
+func (s *derivedSource) receive(index int, ch <-chan float64) {
+	for v := range ch {
+		s.emitMu.Lock()
+		if s.paired {
+			s.pair(index, v)
+		} else {
+			s.update(index, v)
+		}
+		s.emitMu.Unlock()
+		activity.done()
+	}
+
+	// Queued updates of other inputs can no longer be paired
+	s.emitMu.Lock()
+	s.stopped = true
+	clear(s.queues)
+	s.emitMu.Unlock()
+}
+
+// pair queues an update and emits for every tick all inputs delivered.
+// Must be called with s.emitMu held.
+func (s *derivedSource) pair(index int, v float64) {
+	if s.stopped {
+		return
+	}
+	s.queues[index] = append(s.queues[index], v)
+	for !slices.ContainsFunc(s.queues, func(queue []float64) bool { return len(queue) == 0 }) {
+		for i, queue := range s.queues {
+			s.latest[i] = queue[0]
+			s.queues[i] = queue[1:]
+		}
+		s.emit(s.combine(s.latest))
+	}
+}
+
+// update records the latest update of an input and emits once all inputs are fresh.
+// Must be called with s.emitMu held.
+func (s *derivedSource) update(index int, v float64) {
+	s.latest[index] = v
+	s.fresh[index] = true
+	if !slices.Contains(s.fresh, false) {
+		s.emit(s.combine(s.latest))
+		clear(s.fresh)
+	}
+}
+
+// emit fans out a combined value to all subscribers.
+// Must be called with s.emitMu held.
+func (s *derivedSource) emit(result float64) {
+	s.generationCount.Add(1)
+
+	s.mu.Lock()
+	subs := s.subscribers
+	s.mu.Unlock()
+
+	activity.add(len(subs))
+	for _, subChan := range subs {
+		subChan <- result
+	}
+}
+
+// Stats returns generation statistics.
+func (s *derivedSource) Stats() source.SourceStats {
+	s.mu.Lock()
+	subCount := len(s.subscribers)
+	s.mu.Unlock()
+
+	return source.SourceStats{
+		GenerationCount: s.generationCount.Load(),
+		SubscriberCount: subCount,
+	}
+}
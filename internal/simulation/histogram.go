@@ -0,0 +1,65 @@
+package simulation
+
+import (
+	"slices"
+	"sync"
+)
+
+// Histogram aggregates observations into explicit buckets.
+// Observations are typically fed from value updates via ValueWrapper.AddObserver.
+type Histogram struct {
+	bounds []float64
+
+	mu     sync.Mutex
+	counts []uint64 // Per bucket, last entry is the +Inf bucket
+	count  uint64
+	sum    float64
+}
+
+// HistogramSnapshot is a consistent copy of the histogram state.
+type HistogramSnapshot struct {
+	Bounds []float64
+	Counts []uint64 // Per bucket (not cumulative), len(Bounds)+1 with +Inf last
+	Count  uint64
+	Sum    float64
+}
+
+// NewHistogram creates a histogram with the given upper bounds.
+// Bounds must be strictly increasing, the +Inf bucket is implicit.
+func NewHistogram(bounds []float64) *Histogram {
+	return &Histogram{
+		bounds: slices.Clone(bounds),
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+// Observe records a single observation.
+func (h *Histogram) Observe(v float64) {
+	// Upper bounds are inclusive (le)
+	idx, _ := slices.BinarySearch(h.bounds, v)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts[idx]++
+	h.count++
+	h.sum += v
+}
+
+// Bounds returns the bucket upper bounds.
+func (h *Histogram) Bounds() []float64 {
+	return slices.Clone(h.bounds)
+}
+
+// Snapshot returns the current state.
+func (h *Histogram) Snapshot() HistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return HistogramSnapshot{
+		Bounds: h.bounds,
+		Counts: slices.Clone(h.counts),
+		Count:  h.count,
+		Sum:    h.sum,
+	}
+}
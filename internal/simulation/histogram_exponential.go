@@ -0,0 +1,197 @@
+package simulation
+
+import (
+	"maps"
+	"math"
+	"sync"
+	"time"
+)
+
+// minExponentialScale is the lowest scale the histogram downscales to.
+// Matches the lowest Prometheus native histogram schema.
+const minExponentialScale = -4
+
+// ExponentialHistogram aggregates observations into base-2 exponential buckets.
+// Bucket index i at scale s covers (base^i, base^(i+1)] with base = 2^(2^-s),
+// following the OTEL data model. The scale starts at maxScale and is reduced
+// whenever the buckets of one sign would span more than maxBuckets indexes.
+type ExponentialHistogram struct {
+	maxScale   int
+	maxBuckets int
+	created    time.Time
+
+	mu        sync.Mutex
+	scale     int
+	positive  exponentialBuckets
+	negative  exponentialBuckets
+	zeroCount uint64
+	count     uint64
+	sum       float64
+}
+
+// ExponentialHistogramSnapshot is a consistent copy of the histogram state.
+type ExponentialHistogramSnapshot struct {
+	Scale     int
+	Positive  map[int]uint64 // Bucket index to count
+	Negative  map[int]uint64 // Bucket index (of absolute value) to count
+	ZeroCount uint64
+	Count     uint64
+	Sum       float64
+	Created   time.Time
+}
+
+// exponentialBuckets holds sparse bucket counts of one sign.
+type exponentialBuckets struct {
+	counts   map[int]uint64
+	min, max int
+}
+
+// NewExponentialHistogram creates an exponential histogram.
+func NewExponentialHistogram(maxScale, maxBuckets int) *ExponentialHistogram {
+	return &ExponentialHistogram{
+		maxScale:   maxScale,
+		maxBuckets: maxBuckets,
+		created:    Now(),
+		scale:      maxScale,
+		positive:   exponentialBuckets{counts: make(map[int]uint64)},
+		negative:   exponentialBuckets{counts: make(map[int]uint64)},
+	}
+}
+
+// MaxScale returns the initial scale.
+func (h *ExponentialHistogram) MaxScale() int {
+	return h.maxScale
+}
+
+// MaxBuckets returns the bucket limit per sign.
+func (h *ExponentialHistogram) MaxBuckets() int {
+	return h.maxBuckets
+}
+
+// Observe records a single observation.
+func (h *ExponentialHistogram) Observe(v float64) {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.count++
+	h.sum += v
+
+	switch {
+	case v > 0:
+		h.insert(&h.positive, v)
+	case v < 0:
+		h.insert(&h.negative, -v)
+	default:
+		h.zeroCount++
+	}
+}
+
+// insert adds an absolute value to the buckets, downscaling if required.
+// Must be called with h.mu held.
+func (h *ExponentialHistogram) insert(b *exponentialBuckets, v float64) {
+	idx := exponentialIndex(v, h.scale)
+
+	// Reduce resolution until the new index fits into the bucket limit
+	for h.scale > minExponentialScale && b.span(idx) > h.maxBuckets {
+		h.downscale()
+		idx = exponentialIndex(v, h.scale)
+	}
+
+	if len(b.counts) == 0 {
+		b.min, b.max = idx, idx
+	}
+	b.min = min(b.min, idx)
+	b.max = max(b.max, idx)
+	b.counts[idx]++
+}
+
+// downscale halves the resolution of both signs.
+// Must be called with h.mu held.
+func (h *ExponentialHistogram) downscale() {
+	h.scale--
+	h.positive.downscale(1)
+	h.negative.downscale(1)
+}
+
+// Snapshot returns the current state.
+func (h *ExponentialHistogram) Snapshot() ExponentialHistogramSnapshot {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return ExponentialHistogramSnapshot{
+		Scale:     h.scale,
+		Positive:  maps.Clone(h.positive.counts),
+		Negative:  maps.Clone(h.negative.counts),
+		ZeroCount: h.zeroCount,
+		Count:     h.count,
+		Sum:       h.sum,
+		Created:   h.created,
+	}
+}
+
+// Downscale returns the snapshot reduced to the given scale.
+// Returns the snapshot unchanged if its scale is already lower or equal.
+func (s ExponentialHistogramSnapshot) Downscale(scale int) ExponentialHistogramSnapshot {
+	if scale >= s.Scale {
+		return s
+	}
+
+	shift := s.Scale - scale
+	s.Scale = scale
+	s.Positive = mergeExponentialBuckets(s.Positive, shift)
+	s.Negative = mergeExponentialBuckets(s.Negative, shift)
+	return s
+}
+
+// span returns the number of indexes covered when idx is included.
+func (b *exponentialBuckets) span(idx int) int {
+	if len(b.counts) == 0 {
+		return 1
+	}
+	return max(b.max, idx) - min(b.min, idx) + 1
+}
+
+// downscale merges buckets by reducing the scale by shift.
+func (b *exponentialBuckets) downscale(shift int) {
+	if len(b.counts) == 0 {
+		return
+	}
+	b.counts = mergeExponentialBuckets(b.counts, shift)
+	b.min >>= shift
+	b.max >>= shift
+}
+
+// mergeExponentialBuckets maps bucket counts to a scale lower by shift.
+func mergeExponentialBuckets(counts map[int]uint64, shift int) map[int]uint64 {
+	merged := make(map[int]uint64, len(counts))
+	for idx, count := range counts {
+		merged[idx>>shift] += count
+	}
+	return merged
+}
+
+// exponentialIndex returns the bucket index of a positive value at the given scale.
+func exponentialIndex(v float64, scale int) int {
+	// v = frac * 2^exp with frac in [0.5, 1)
+	frac, exp := math.Frexp(v)
+	exp-- // v = 2*frac * 2^exp with 2*frac in [1, 2)
+
+	// Exact powers of two are the inclusive upper bound of their bucket
+	if frac == 0.5 {
+		if scale >= 0 {
+			return exp<<scale - 1
+		}
+		return (exp - 1) >> -scale
+	}
+
+	if scale <= 0 {
+		return exp >> -scale
+	}
+
+	// ceil(log2(v) * 2^scale) - 1, split into exact and fractional part
+	return exp<<scale + int(math.Ceil(math.Log2(2*frac)*float64(int(1)<<scale))) - 1
+}
@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"math/rand/v2"
+
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/seed"
+)
+
+// CreateRestartClock creates the clock triggering restarts.
+// Each tick of clk restarts with the given probability. The draw is made
+// once per tick for all subscribers, so values sharing the restart clock
+// restart together like metrics of one process.
+//
+// The restart clock does not own clk: Start and Stop must be called on clk.
+func CreateRestartClock(clk clock.Clock, probability float64) clock.Clock {
+	if probability >= 1 {
+		return clk
+	}
+	return newBroadcastClock(&sampledClock{
+		Clock:       clk,
+		probability: probability,
+		rng:         seed.NewRand(),
+	})
+}
+
+// sampledClock forwards ticks of the embedded clock with a fixed probability.
+type sampledClock struct {
+	clock.Clock
+	probability float64
+	rng         *rand.Rand
+}
+
+// Subscribe returns a channel receiving the sampled ticks.
+// The channel is closed once the embedded clock stops.
+func (c *sampledClock) Subscribe() <-chan struct{} {
+	ticks := c.Clock.Subscribe()
+	sampled := make(chan struct{})
+
+	go func() {
+		defer close(sampled)
+		for range ticks {
+			if c.rng.Float64() < c.probability {
+				activity.add(1)
+				sampled <- struct{}{}
+			}
+			activity.done()
+		}
+	}()
+
+	return sampled
+}
@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/neox5/otelbox/internal/config"
 	"github.com/neox5/simv/clock"
@@ -9,10 +10,32 @@ import (
 )
 
 // CreateSource creates a source from configuration.
-func CreateSource(cfg config.SourceConfig, clk clock.Clock) (source.Publisher[int], error) {
+// A configured load profile multiplies the emitted values.
+func CreateSource(cfg config.SourceConfig, clk clock.Clock) (source.Publisher[float64], error) {
+	src, err := createSource(cfg, clk)
+	if err != nil || cfg.Profile == nil {
+		return src, err
+	}
+	return newProfileSource(src, *cfg.Profile), nil
+}
+
+// createSource creates the source of the configured type.
+func createSource(cfg config.SourceConfig, clk clock.Clock) (source.Publisher[float64], error) {
 	switch cfg.Type {
 	case "random_int":
-		return source.NewRandomIntSource(clk, cfg.Min, cfg.Max), nil
+		return newRandomIntSource(cfg, clk), nil
+	case "random_float":
+		return newRandomFloatSource(cfg, clk), nil
+	case "sine":
+		return newWaveSource(cfg, clk, math.Sin), nil
+	case "cosine":
+		return newWaveSource(cfg, clk, math.Cos), nil
+	case "random_walk":
+		return newRandomWalkSource(cfg, clk), nil
+	case "normal", "exponential", "poisson", "lognormal":
+		return newDistributionSource(clk, distributionSampler(cfg)), nil
+	case "replay":
+		return newReplaySource(cfg, clk), nil
 	default:
 		return nil, fmt.Errorf("unknown source type: %s", cfg.Type)
 	}
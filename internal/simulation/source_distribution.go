@@ -0,0 +1,87 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/seed"
+	"github.com/neox5/simv/source"
+)
+
+// newDistributionSource creates a source drawing one sample per tick.
+// The sampler receives a generator from the seed registry for reproducibility.
+func newDistributionSource(clk clock.Clock, sample func(rng *rand.Rand) float64) source.Publisher[float64] {
+	rng := seed.NewRand()
+
+	return newTickSource(clk, func(tick uint64) (float64, bool) {
+		return sample(rng), true
+	})
+}
+
+// distributionSampler returns the sampler for a distribution source type.
+func distributionSampler(cfg config.SourceConfig) func(rng *rand.Rand) float64 {
+	switch cfg.Type {
+	case "normal":
+		return func(rng *rand.Rand) float64 {
+			return cfg.Mean + rng.NormFloat64()*cfg.Stddev
+		}
+	case "exponential":
+		return func(rng *rand.Rand) float64 {
+			return rng.ExpFloat64() / cfg.Lambda
+		}
+	case "poisson":
+		return func(rng *rand.Rand) float64 {
+			return float64(samplePoisson(rng, cfg.Lambda))
+		}
+	case "lognormal":
+		return func(rng *rand.Rand) float64 {
+			return math.Exp(cfg.Mu + rng.NormFloat64()*cfg.Sigma)
+		}
+	default:
+		return nil
+	}
+}
+
+// samplePoisson draws a Poisson distributed count.
+// Uses Knuth's multiplication method for small lambda and the
+// transformed rejection method (PTRS, Hörmann 1993) for large lambda.
+func samplePoisson(rng *rand.Rand, lambda float64) int {
+	if lambda < 30 {
+		limit := math.Exp(-lambda)
+		k := 0
+		p := rng.Float64()
+		for p > limit {
+			k++
+			p *= rng.Float64()
+		}
+		return k
+	}
+
+	slam := math.Sqrt(lambda)
+	loglam := math.Log(lambda)
+	b := 0.931 + 2.53*slam
+	a := -0.059 + 0.02483*b
+	invalpha := 1.1239 + 1.1328/(b-3.4)
+	vr := 0.9277 - 3.6224/(b-2)
+
+	for {
+		u := rng.Float64() - 0.5
+		v := rng.Float64()
+		us := 0.5 - math.Abs(u)
+		k := math.Floor((2*a/us+b)*u + lambda + 0.43)
+
+		if us >= 0.07 && v <= vr {
+			return int(k)
+		}
+		if k < 0 || (us < 0.013 && v > us) {
+			continue
+		}
+
+		lg, _ := math.Lgamma(k + 1)
+		if math.Log(v)+math.Log(invalpha)-math.Log(a/(us*us)+b) <= -lambda+k*loglam-lg {
+			return int(k)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/source"
+	"github.com/neox5/simv/value"
+)
+
+// newProfileSource multiplies the values of src by the load profile factor
+// at the time of emission. The factor is computed once per value, so all
+// subscribers of a shared source receive the same value.
+func newProfileSource(src source.Publisher[float64], profile config.ProfileConfig) source.Publisher[float64] {
+	loc := profile.Location()
+	linear := profile.Interpolation == config.ProfileInterpolationLinear
+
+	return newDerivedSource([]value.Publisher[float64]{src}, func(values []float64) float64 {
+		return values[0] * profileFactor(profile, linear, Now().In(loc))
+	}, true)
+}
+
+// profileFactor returns the product of the hour and day factor at t.
+// Table entries apply from the start of their hour or day. Linear
+// interpolation moves towards the next entry until it applies.
+func profileFactor(profile config.ProfileConfig, linear bool, t time.Time) float64 {
+	hour := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	day := float64((int(t.Weekday())+6)%7) + hour/24 // Monday is 0
+
+	return profileLookup(profile.Hours, hour, linear) * profileLookup(profile.Days, day, linear)
+}
+
+// profileLookup returns the table entry at position pos, 1 for an empty table.
+func profileLookup(table []float64, pos float64, linear bool) float64 {
+	if len(table) == 0 {
+		return 1
+	}
+	i := int(pos)
+	if !linear {
+		return table[i]
+	}
+	frac := pos - float64(i)
+	return table[i]*(1-frac) + table[(i+1)%len(table)]*frac
+}
@@ -0,0 +1,29 @@
+package simulation
+
+import (
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/seed"
+	"github.com/neox5/simv/source"
+)
+
+// newRandomIntSource creates a source of uniformly distributed integers in [Min, Max].
+// Draws the same sequence as simv's RandomIntSource for a given seed.
+func newRandomIntSource(cfg config.SourceConfig, clk clock.Clock) source.Publisher[float64] {
+	rng := seed.NewRand()
+	lower := int(cfg.Min)
+	span := int(cfg.Max) - lower + 1
+
+	return newTickSource(clk, func(tick uint64) (float64, bool) {
+		return float64(lower + rng.IntN(span)), true
+	})
+}
+
+// newRandomFloatSource creates a source of uniformly distributed floats in [Min, Max).
+func newRandomFloatSource(cfg config.SourceConfig, clk clock.Clock) source.Publisher[float64] {
+	rng := seed.NewRand()
+
+	return newTickSource(clk, func(tick uint64) (float64, bool) {
+		return cfg.Min + rng.Float64()*(cfg.Max-cfg.Min), true
+	})
+}
@@ -0,0 +1,20 @@
+package simulation
+
+import (
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/source"
+)
+
+// newReplaySource creates a source emitting recorded samples, one per tick.
+// Samples are read from the file during config resolution.
+// At the end of the recording the source either starts over or stops emitting.
+func newReplaySource(cfg config.SourceConfig, clk clock.Clock) source.Publisher[float64] {
+	samples := cfg.Samples
+	return newTickSource(clk, func(tick uint64) (float64, bool) {
+		if tick >= uint64(len(samples)) && !cfg.Loop {
+			return 0, false
+		}
+		return samples[tick%uint64(len(samples))], true
+	})
+}
@@ -0,0 +1,58 @@
+package simulation
+
+import (
+	"math"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/seed"
+	"github.com/neox5/simv/source"
+)
+
+// newRandomWalkSource creates a bounded random walk source.
+// Each tick adds a random step plus drift to the previous position.
+// Positions leaving [Min, Max] are clamped or reflected back into range.
+func newRandomWalkSource(cfg config.SourceConfig, clk clock.Clock) source.Publisher[float64] {
+	rng := seed.NewRand()
+	lower := cfg.Min
+	upper := cfg.Max
+	position := cfg.Start
+
+	return newTickSource(clk, func(tick uint64) (float64, bool) {
+		// First tick emits the start position
+		if tick > 0 {
+			var step float64
+			switch cfg.StepDistribution {
+			case config.StepDistributionNormal:
+				step = rng.NormFloat64() * cfg.Step
+			default:
+				step = (rng.Float64()*2 - 1) * cfg.Step
+			}
+
+			position += step + cfg.Drift
+
+			switch cfg.Boundary {
+			case config.BoundaryReflect:
+				position = reflect(position, lower, upper)
+			default:
+				position = math.Max(lower, math.Min(upper, position))
+			}
+		}
+
+		return position, true
+	})
+}
+
+// reflect mirrors x at the bounds until it lies within [lower, upper].
+func reflect(x, lower, upper float64) float64 {
+	// Reflection is periodic with twice the range width
+	width := upper - lower
+	offset := math.Mod(x-lower, 2*width)
+	if offset < 0 {
+		offset += 2 * width
+	}
+	if offset > width {
+		offset = 2*width - offset
+	}
+	return lower + offset
+}
@@ -0,0 +1,35 @@
+package simulation
+
+import (
+	"math"
+
+	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/source"
+)
+
+// newWaveSource creates a source following a periodic waveform.
+// With a periodic clock the position within the period is derived from the
+// tick count and the clock interval, so the emitted sequence is identical
+// across runs. Other clocks tick irregularly, a tick count would drift from
+// the simulation time, so the position is the time elapsed since creation.
+func newWaveSource(cfg config.SourceConfig, clk clock.Clock, wave func(float64) float64) source.Publisher[float64] {
+	step := cfg.Clock.Interval.Seconds()
+	period := cfg.Period.Seconds()
+	phase := cfg.Phase.Seconds()
+
+	position := func(tick uint64) float64 {
+		return float64(tick) * step
+	}
+	if cfg.Clock.Type != "periodic" {
+		start := Now()
+		position = func(uint64) float64 {
+			return Now().Sub(start).Seconds()
+		}
+	}
+
+	return newTickSource(clk, func(tick uint64) (float64, bool) {
+		t := position(tick) + phase
+		return cfg.Offset + cfg.Amplitude*wave(2*math.Pi*t/period), true
+	})
+}
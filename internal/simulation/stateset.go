@@ -0,0 +1,93 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sync"
+
+	"github.com/neox5/simv/seed"
+)
+
+// StateSet tracks the single active state of a state machine.
+// Each observation either selects the state by index (value mode) or
+// performs a weighted random transition from the active state.
+type StateSet struct {
+	states      []string
+	transitions [][]float64 // Cumulative weights per state, nil in value mode
+	rng         *rand.Rand
+
+	mu     sync.Mutex
+	active int
+}
+
+// NewStateSet creates a state set starting in initial.
+// With nil transitions, observed values select the state by index.
+// Otherwise every observation moves to a next state drawn from the weights
+// of the active state; states without weights keep their state.
+func NewStateSet(states []string, initial string, transitions map[string]map[string]float64) *StateSet {
+	s := &StateSet{
+		states: slices.Clone(states),
+		active: max(0, slices.Index(states, initial)),
+	}
+
+	if transitions != nil {
+		// Weights are ordered by state index, map iteration order must not affect draws
+		s.transitions = make([][]float64, len(states))
+		for i, from := range states {
+			weights, ok := transitions[from]
+			if !ok {
+				continue
+			}
+			cumulative := make([]float64, len(states))
+			var total float64
+			for j, to := range states {
+				total += weights[to]
+				cumulative[j] = total
+			}
+			s.transitions[i] = cumulative
+		}
+		s.rng = seed.NewRand()
+	}
+
+	return s
+}
+
+// States returns all state names in configured order.
+func (s *StateSet) States() []string {
+	return slices.Clone(s.states)
+}
+
+// Observe updates the active state from a value update.
+func (s *StateSet) Observe(v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.transitions == nil {
+		// Value selects the state, out of range values are clamped
+		if !math.IsNaN(v) {
+			s.active = int(max(0, min(math.Round(v), float64(len(s.states)-1))))
+		}
+		return
+	}
+
+	cumulative := s.transitions[s.active]
+	if cumulative == nil {
+		return
+	}
+
+	target := s.rng.Float64() * cumulative[len(cumulative)-1]
+	for i, bound := range cumulative {
+		if target < bound {
+			s.active = i
+			return
+		}
+	}
+}
+
+// Active returns the index of the active state.
+func (s *StateSet) Active() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.active
+}
@@ -0,0 +1,88 @@
+package simulation
+
+import (
+	"math"
+	"slices"
+	"sync"
+)
+
+// Summary computes quantiles over a sliding window of the most recent observations.
+// Sum and count cover all observations since creation.
+type Summary struct {
+	quantiles []float64
+
+	mu     sync.Mutex
+	window []float64 // Ring buffer, next is the oldest entry once full
+	next   int
+	full   bool
+	count  uint64
+	sum    float64
+}
+
+// SummarySnapshot is a consistent copy of the summary state.
+type SummarySnapshot struct {
+	Quantiles map[float64]float64 // Quantile to value, NaN while no observations exist
+	Count     uint64
+	Sum       float64
+}
+
+// NewSummary creates a summary over the given number of observations.
+func NewSummary(quantiles []float64, window int) *Summary {
+	return &Summary{
+		quantiles: slices.Clone(quantiles),
+		window:    make([]float64, window),
+	}
+}
+
+// Quantiles returns the configured quantiles in ascending order.
+func (s *Summary) Quantiles() []float64 {
+	return slices.Clone(s.quantiles)
+}
+
+// Observe records a single observation, replacing the oldest one if the window is full.
+func (s *Summary) Observe(v float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.window[s.next] = v
+	s.next++
+	if s.next == len(s.window) {
+		s.next = 0
+		s.full = true
+	}
+
+	s.count++
+	s.sum += v
+}
+
+// Snapshot returns the current quantiles, count and sum.
+func (s *Summary) Snapshot() SummarySnapshot {
+	s.mu.Lock()
+	observations := slices.Clone(s.window[:s.next])
+	if s.full {
+		observations = slices.Clone(s.window)
+	}
+	snapshot := SummarySnapshot{
+		Quantiles: make(map[float64]float64, len(s.quantiles)),
+		Count:     s.count,
+		Sum:       s.sum,
+	}
+	s.mu.Unlock()
+
+	// Sort outside the lock, observations keep arriving
+	slices.Sort(observations)
+	for _, q := range s.quantiles {
+		snapshot.Quantiles[q] = quantile(observations, q)
+	}
+
+	return snapshot
+}
+
+// quantile returns the nearest-rank quantile of sorted observations.
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(0, min(rank, len(sorted)-1))]
+}
@@ -0,0 +1,89 @@
+package simulation
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/source"
+)
+
+// tickSource emits a computed value on every clock tick.
+// Mirrors the subscription model of simv sources: the clock is subscribed
+// lazily on first Subscribe and every subscriber receives each value.
+type tickSource[T any] struct {
+	clock clock.Clock
+	next  func(tick uint64) (T, bool) // Computes value for 0-based tick index, false skips the tick
+
+	initOnce        sync.Once
+	clockChan       <-chan struct{}
+	mu              sync.Mutex
+	subscribers     []chan T
+	generationCount atomic.Uint64
+}
+
+// newTickSource creates a source that calls next for every clock tick.
+func newTickSource[T any](clk clock.Clock, next func(tick uint64) (T, bool)) *tickSource[T] {
+	return &tickSource[T]{
+		clock: clk,
+		next:  next,
+	}
+}
+
+// Subscribe returns a channel receiving every generated value.
+func (s *tickSource[T]) Subscribe() <-chan T {
+	s.initOnce.Do(func() {
+		s.clockChan = s.clock.Subscribe()
+		go s.run()
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := make(chan T)
+	s.subscribers = append(s.subscribers, ch)
+	return ch
+}
+
+// run generates a value per tick and fans it out to all subscribers.
+func (s *tickSource[T]) run() {
+	var tick uint64
+	for range s.clockChan {
+		value, ok := s.next(tick)
+		tick++
+		if !ok {
+			activity.done()
+			continue
+		}
+		s.generationCount.Add(1)
+
+		s.mu.Lock()
+		subs := s.subscribers
+		s.mu.Unlock()
+
+		activity.add(len(subs))
+		for _, subChan := range subs {
+			subChan <- value
+		}
+		activity.done()
+	}
+
+	// Clock stopped - close subscriber channels
+	s.mu.Lock()
+	for _, subChan := range s.subscribers {
+		close(subChan)
+	}
+	s.mu.Unlock()
+}
+
+// Stats returns generation statistics.
+func (s *tickSource[T]) Stats() source.SourceStats {
+	s.mu.Lock()
+	subCount := len(s.subscribers)
+	s.mu.Unlock()
+
+	return source.SourceStats{
+		GenerationCount: s.generationCount.Load(),
+		SubscriberCount: subCount,
+	}
+}
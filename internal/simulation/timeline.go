@@ -0,0 +1,294 @@
+package simulation
+
+import (
+	"container/heap"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/neox5/otelbox/internal/config"
+)
+
+// timeline drives virtual time, nil in real time.
+// Clocks and exporters schedule events at virtual times instead of waiting
+// on real timers, the timeline fires them in order. At a speed factor events
+// fire when real time catches up with the scaled virtual time. At maximum
+// speed they fire back to back, each once the previous one is fully processed.
+var timeline *scheduler
+
+// InitializeTime configures the time base of the simulation.
+// Must be called before creating any clocks.
+func InitializeTime(cfg *config.SettingsConfig) {
+	timeline = nil
+	activity.enabled = false
+
+	if !cfg.Time.Virtual() {
+		return
+	}
+
+	timeline = newScheduler(cfg.Time)
+	activity.enabled = cfg.Time.MaxSpeed()
+
+	speed := "max"
+	if !cfg.Time.MaxSpeed() {
+		speed = strconv.FormatFloat(cfg.Time.Speed, 'g', -1, 64)
+	}
+	attrs := []any{"start", cfg.Time.Start.Format(time.RFC3339), "speed", speed}
+	if !cfg.Time.End.IsZero() {
+		attrs = append(attrs, "end", cfg.Time.End.Format(time.RFC3339))
+	}
+	slog.Info("virtual time initialized", attrs...)
+}
+
+// VirtualTime reports whether the simulation runs on virtual time.
+func VirtualTime() bool {
+	return timeline != nil
+}
+
+// Now returns the current simulation time, real time unless virtual.
+func Now() time.Time {
+	if timeline == nil {
+		return time.Now()
+	}
+	return timeline.now()
+}
+
+// TimelineStart returns the virtual time the simulation started at.
+// Returns the zero time in real time.
+func TimelineStart() time.Time {
+	if timeline == nil {
+		return time.Time{}
+	}
+	return timeline.start
+}
+
+// Every calls fn at every interval of virtual time with the current time.
+// At maximum speed fn runs once all ticks up to that time are processed.
+// Does nothing in real time.
+func Every(interval time.Duration, fn func(now time.Time)) {
+	if timeline == nil {
+		return
+	}
+	var fire func(at time.Time)
+	fire = func(at time.Time) {
+		fn(at)
+		timeline.schedule(at.Add(interval), eventExport, fire)
+	}
+	timeline.schedule(timeline.start.Add(interval), eventExport, fire)
+}
+
+// RunTimeline starts advancing virtual time.
+// Must be called after starting the clocks. Does nothing in real time.
+func RunTimeline() {
+	if timeline == nil {
+		return
+	}
+	timeline.startOnce.Do(func() {
+		timeline.mu.Lock()
+		timeline.realStart = time.Now()
+		timeline.running = true
+		timeline.mu.Unlock()
+		go timeline.run()
+	})
+}
+
+// StopTimeline stops advancing virtual time.
+// Returns once the event in progress is processed.
+func StopTimeline() {
+	if timeline == nil {
+		return
+	}
+	timeline.stopOnce.Do(func() {
+		close(timeline.stop)
+	})
+	timeline.mu.Lock()
+	running := timeline.running
+	timeline.mu.Unlock()
+	if running {
+		<-timeline.done
+	}
+}
+
+// TimelineDone returns a channel closed once virtual time reached the end.
+// Returns nil in real time or without end, receiving from it blocks forever.
+func TimelineDone() <-chan struct{} {
+	if timeline == nil || timeline.end.IsZero() {
+		return nil
+	}
+	return timeline.finished
+}
+
+// Event order at equal times: exports see all ticks of their time.
+const (
+	eventTick = iota
+	eventExport
+)
+
+// event is a call scheduled at a virtual time.
+type event struct {
+	at    time.Time
+	order int    // eventTick or eventExport
+	seq   uint64 // Scheduling order, keeps equal events first in first out
+	fire  func(at time.Time)
+}
+
+// eventQueue is a min heap of events implementing heap.Interface.
+type eventQueue []*event
+
+func (q eventQueue) Len() int { return len(q) }
+
+func (q eventQueue) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	if q[i].order != q[j].order {
+		return q[i].order < q[j].order
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q eventQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *eventQueue) Push(x any) { *q = append(*q, x.(*event)) }
+
+func (q *eventQueue) Pop() any {
+	old := *q
+	ev := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return ev
+}
+
+// scheduler fires scheduled events in virtual time order.
+type scheduler struct {
+	start time.Time
+	end   time.Time // Zero runs until stopped
+	speed float64   // 0 fires as fast as possible
+
+	mu        sync.Mutex
+	events    eventQueue
+	seq       uint64
+	current   time.Time // Time of the last fired event, maximum speed only
+	realStart time.Time // Real time at start, scaled speed only
+	running   bool
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	wake      chan struct{} // Signals newly scheduled events
+	stop      chan struct{}
+	done      chan struct{} // Closed when run returns
+	finished  chan struct{} // Closed when the end is reached
+}
+
+func newScheduler(cfg config.TimeConfig) *scheduler {
+	return &scheduler{
+		start:    cfg.Start,
+		end:      cfg.End,
+		speed:    cfg.Speed,
+		current:  cfg.Start,
+		wake:     make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		finished: make(chan struct{}),
+	}
+}
+
+// now returns the current virtual time.
+func (s *scheduler) now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.speed == 0 || !s.running {
+		return s.current
+	}
+	now := s.start.Add(time.Duration(float64(time.Since(s.realStart)) * s.speed))
+	if !s.end.IsZero() && now.After(s.end) {
+		return s.end
+	}
+	return now
+}
+
+// realTime returns the real time virtual time t is reached at scaled speed.
+// Must be called with s.mu held.
+func (s *scheduler) realTime(t time.Time) time.Time {
+	return s.realStart.Add(time.Duration(float64(t.Sub(s.start)) / s.speed))
+}
+
+// schedule adds an event firing at virtual time at.
+func (s *scheduler) schedule(at time.Time, order int, fire func(at time.Time)) {
+	s.mu.Lock()
+	s.seq++
+	heap.Push(&s.events, &event{at: at, order: order, seq: s.seq, fire: fire})
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run fires events until stopped or the end is reached.
+func (s *scheduler) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		// Next event, or the end if it comes first
+		s.mu.Lock()
+		var next *event
+		at := s.end
+		if len(s.events) > 0 && (at.IsZero() || !s.events[0].at.After(at)) {
+			next = s.events[0]
+			at = next.at
+		}
+
+		if at.IsZero() {
+			// Nothing scheduled and no end
+			s.mu.Unlock()
+			select {
+			case <-s.wake:
+				continue
+			case <-s.stop:
+				return
+			}
+		}
+
+		if s.speed > 0 {
+			if wait := time.Until(s.realTime(at)); wait > 0 {
+				s.mu.Unlock()
+				timer.Reset(wait)
+				select {
+				case <-timer.C:
+				case <-s.wake:
+				case <-s.stop:
+					return
+				}
+				continue
+			}
+		}
+
+		s.current = at
+		if next == nil {
+			s.mu.Unlock()
+			slog.Info("virtual time reached end", "end", at.Format(time.RFC3339))
+			close(s.finished)
+			return
+		}
+		heap.Pop(&s.events)
+		s.mu.Unlock()
+
+		next.fire(at)
+
+		// Maximum speed advances once the simulation settled
+		activity.wait()
+
+		select {
+		case <-s.stop:
+			return
+		default:
+		}
+	}
+}
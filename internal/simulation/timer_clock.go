@@ -0,0 +1,150 @@
+package simulation
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/neox5/otelbox/internal/cron"
+	"github.com/neox5/simv/clock"
+	"github.com/neox5/simv/seed"
+)
+
+// timerClock ticks after intervals computed for every tick.
+// In virtual time ticks are scheduled on the timeline instead of real timers.
+type timerClock struct {
+	mean      time.Duration                     // Reported as interval in stats
+	next      func(now time.Time) time.Duration // Interval until the tick following now
+	tickChan  chan struct{}
+	stop      chan struct{}
+	wg        sync.WaitGroup
+	tickCount atomic.Uint64
+	running   atomic.Bool
+
+	// Clocks of shared source instances may never be subscribed,
+	// their ticks must not block the timeline
+	subscribed atomic.Bool
+}
+
+// newJitterClock creates a clock ticking every interval ± jitter.
+// Each interval is drawn uniformly from [interval-jitter, interval+jitter]
+// using a seeded RNG, so the sequence of intervals is reproducible.
+func newJitterClock(interval, jitter time.Duration) *timerClock {
+	rng := seed.NewRand()
+	return newTimerClock(interval, func(time.Time) time.Duration {
+		return interval + time.Duration((2*rng.Float64()-1)*float64(jitter))
+	})
+}
+
+// newPoissonClock creates a clock with exponentially distributed intervals.
+// Ticks form a Poisson process with the given mean rate per second. Intervals
+// are drawn using a seeded RNG, so the sequence of intervals is reproducible.
+func newPoissonClock(rate float64) *timerClock {
+	rng := seed.NewRand()
+	mean := time.Duration(float64(time.Second) / rate)
+	return newTimerClock(mean, func(time.Time) time.Duration {
+		return time.Duration(rng.ExpFloat64() * float64(mean))
+	})
+}
+
+// newCronClock creates a clock ticking on the activations of a cron schedule.
+// Activations are computed in the given location.
+func newCronClock(schedule *cron.Schedule, loc *time.Location) *timerClock {
+	return newTimerClock(schedule.MeanInterval(), func(now time.Time) time.Duration {
+		now = now.In(loc)
+		next := schedule.Next(now)
+		if next.IsZero() {
+			// No activation within the search window, check again later
+			return 24 * time.Hour
+		}
+		return next.Sub(now)
+	})
+}
+
+// newFixedClock creates a clock ticking every interval.
+// Replaces simv periodic clocks in virtual time.
+func newFixedClock(interval time.Duration) *timerClock {
+	return newTimerClock(interval, func(time.Time) time.Duration {
+		return interval
+	})
+}
+
+func newTimerClock(mean time.Duration, next func(now time.Time) time.Duration) *timerClock {
+	return &timerClock{
+		mean:     mean,
+		next:     next,
+		tickChan: make(chan struct{}),
+		stop:     make(chan struct{}),
+	}
+}
+
+// Start begins generating ticks.
+func (c *timerClock) Start() {
+	c.running.Store(true)
+	if timeline != nil {
+		start := timeline.start
+		timeline.schedule(start.Add(c.next(start)), eventTick, c.fire)
+		return
+	}
+	c.wg.Go(c.run)
+}
+
+func (c *timerClock) run() {
+	timer := time.NewTimer(c.next(time.Now()))
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			c.tickCount.Add(1)
+			select {
+			case c.tickChan <- struct{}{}:
+			case <-c.stop:
+				return
+			}
+			timer.Reset(c.next(time.Now()))
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// fire delivers a tick at virtual time at and schedules the next one.
+// Called by the timeline.
+func (c *timerClock) fire(at time.Time) {
+	c.tickCount.Add(1)
+	if c.subscribed.Load() {
+		activity.add(1)
+		select {
+		case c.tickChan <- struct{}{}:
+		case <-timeline.stop:
+			activity.done()
+			return
+		}
+	}
+	timeline.schedule(at.Add(c.next(at)), eventTick, c.fire)
+}
+
+// Stop stops the clock and closes the tick channel.
+// In virtual time the timeline must be stopped first.
+func (c *timerClock) Stop() {
+	c.running.Store(false)
+	close(c.stop)
+	c.wg.Wait()
+	close(c.tickChan)
+}
+
+// Subscribe returns the channel that receives tick events.
+func (c *timerClock) Subscribe() <-chan struct{} {
+	c.subscribed.Store(true)
+	return c.tickChan
+}
+
+// Stats returns current clock metrics.
+func (c *timerClock) Stats() clock.ClockStats {
+	return clock.ClockStats{
+		TickCount: c.tickCount.Load(),
+		IsRunning: c.running.Load(),
+		Interval:  c.mean,
+	}
+}
@@ -0,0 +1,239 @@
+package simulation
+
+import (
+	"math"
+	"time"
+
+	"github.com/neox5/simv/transform"
+)
+
+// accumulate adds each value to a running total.
+// As last transform it continues from the value state, so a reset of the
+// value (reset on read) restarts the total. Earlier in the chain the value
+// state holds the output of later transforms, so it keeps its own total.
+type accumulate struct {
+	fromState bool
+	total     float64
+}
+
+func newAccumulate(last bool) *accumulate {
+	return &accumulate{fromState: last}
+}
+
+// Apply implements transform.Transformation.
+func (t *accumulate) Apply(incoming float64, state transform.State[float64]) float64 {
+	if t.fromState {
+		return state.GetState() + incoming
+	}
+	t.total += incoming
+	return t.total
+}
+
+// Name implements transform.Transformation.
+func (t *accumulate) Name() string {
+	return "accumulate"
+}
+
+// delta emits the change of the incoming value since the previous update.
+// The first update is the baseline and emits 0.
+type delta struct {
+	previous float64
+	started  bool
+}
+
+// Apply implements transform.Transformation.
+func (t *delta) Apply(incoming float64, state transform.State[float64]) float64 {
+	if !t.started {
+		t.previous = incoming
+		t.started = true
+		return 0
+	}
+	change := incoming - t.previous
+	t.previous = incoming
+	return change
+}
+
+// Name implements transform.Transformation.
+func (t *delta) Name() string {
+	return "delta"
+}
+
+// rate emits the change since the previous update per second.
+// The change is normalized by the nominal clock interval, not wall time,
+// so results are reproducible.
+type rate struct {
+	delta
+	seconds float64
+}
+
+func newRate(interval time.Duration) *rate {
+	return &rate{seconds: interval.Seconds()}
+}
+
+// Apply implements transform.Transformation.
+func (t *rate) Apply(incoming float64, state transform.State[float64]) float64 {
+	return t.delta.Apply(incoming, state) / t.seconds
+}
+
+// Name implements transform.Transformation.
+func (t *rate) Name() string {
+	return "rate"
+}
+
+// scale multiplies the incoming value by a constant factor.
+type scale struct {
+	factor float64
+}
+
+// Apply implements transform.Transformation.
+func (t *scale) Apply(incoming float64, state transform.State[float64]) float64 {
+	return incoming * t.factor
+}
+
+// Name implements transform.Transformation.
+func (t *scale) Name() string {
+	return "scale"
+}
+
+// offset adds a constant to the incoming value.
+type offset struct {
+	offset float64
+}
+
+// Apply implements transform.Transformation.
+func (t *offset) Apply(incoming float64, state transform.State[float64]) float64 {
+	return incoming + t.offset
+}
+
+// Name implements transform.Transformation.
+func (t *offset) Name() string {
+	return "offset"
+}
+
+// clamp limits the incoming value to [min, max].
+// An unset bound is infinite.
+type clamp struct {
+	min, max float64
+}
+
+func newClamp(lower, upper *float64) *clamp {
+	t := &clamp{min: math.Inf(-1), max: math.Inf(1)}
+	if lower != nil {
+		t.min = *lower
+	}
+	if upper != nil {
+		t.max = *upper
+	}
+	return t
+}
+
+// Apply implements transform.Transformation.
+func (t *clamp) Apply(incoming float64, state transform.State[float64]) float64 {
+	return max(t.min, min(incoming, t.max))
+}
+
+// Name implements transform.Transformation.
+func (t *clamp) Name() string {
+	return "clamp"
+}
+
+// quantize rounds the incoming value to the nearest multiple of step.
+type quantize struct {
+	step float64
+}
+
+// Apply implements transform.Transformation.
+func (t *quantize) Apply(incoming float64, state transform.State[float64]) float64 {
+	return math.Round(incoming/t.step) * t.step
+}
+
+// Name implements transform.Transformation.
+func (t *quantize) Name() string {
+	return "quantize"
+}
+
+// movingAverage emits the mean of the last window incoming values.
+// Until the window is filled, the mean covers all values seen so far.
+type movingAverage struct {
+	window []float64 // Ring buffer, next is the oldest entry once full
+	next   int
+	full   bool
+	sum    float64
+}
+
+func newMovingAverage(window int) *movingAverage {
+	return &movingAverage{window: make([]float64, window)}
+}
+
+// Apply implements transform.Transformation.
+func (t *movingAverage) Apply(incoming float64, state transform.State[float64]) float64 {
+	t.sum += incoming - t.window[t.next]
+	t.window[t.next] = incoming
+	t.next++
+	if t.next == len(t.window) {
+		t.next = 0
+		t.full = true
+	}
+
+	n := t.next
+	if t.full {
+		n = len(t.window)
+		// Recompute periodically so floating point drift does not accumulate
+		if t.next == 0 {
+			t.sum = 0
+			for _, v := range t.window {
+				t.sum += v
+			}
+		}
+	}
+	return t.sum / float64(n)
+}
+
+// Name implements transform.Transformation.
+func (t *movingAverage) Name() string {
+	return "moving_average"
+}
+
+// ewma emits the exponentially weighted moving average of incoming values.
+// The first value initializes the average.
+type ewma struct {
+	alpha   float64
+	average float64
+	started bool
+}
+
+// Apply implements transform.Transformation.
+func (t *ewma) Apply(incoming float64, state transform.State[float64]) float64 {
+	if !t.started {
+		t.average = incoming
+		t.started = true
+		return t.average
+	}
+	t.average += t.alpha * (incoming - t.average)
+	return t.average
+}
+
+// Name implements transform.Transformation.
+func (t *ewma) Name() string {
+	return "ewma"
+}
+
+// wrap continues from reset once the incoming value reaches threshold,
+// keeping the excess like an overflowing counter.
+type wrap struct {
+	threshold float64
+	reset     float64
+}
+
+// Apply implements transform.Transformation.
+func (t *wrap) Apply(incoming float64, state transform.State[float64]) float64 {
+	if incoming < t.threshold {
+		return incoming
+	}
+	return t.reset + math.Mod(incoming-t.reset, t.threshold-t.reset)
+}
+
+// Name implements transform.Transformation.
+func (t *wrap) Name() string {
+	return "wrap"
+}
@@ -2,8 +2,12 @@ package simulation
 
 import (
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/neox5/otelbox/internal/config"
+	"github.com/neox5/simv/clock"
 	"github.com/neox5/simv/source"
 	"github.com/neox5/simv/transform"
 	"github.com/neox5/simv/value"
@@ -11,14 +15,121 @@ import (
 
 // ValueWrapper wraps simv Value for easier management
 type ValueWrapper struct {
-	*value.Value[int]
+	*value.Value[float64]
+	updates *updateObservers
+	reads   *readReset   // Reset on read settings, applied by cursors
+	created atomic.Int64 // Unix nanoseconds of creation or last restart
+}
+
+// AddObserver registers a function called with the final state of every update.
+// Observers run synchronously on the value's update goroutine.
+func (w *ValueWrapper) AddObserver(observer func(float64)) {
+	w.updates.add(observer)
+}
+
+// Subscribe returns a channel receiving the final state of every update.
+// Lets values feed derived values. The channel is closed once the source
+// stopped and the last update was processed.
+func (w *ValueWrapper) Subscribe() <-chan float64 {
+	return w.updates.subscribe()
+}
+
+// Peek returns the current value.
+// Reset on read never resets the value itself, only the cursors of exporters.
+func (w *ValueWrapper) Peek() float64 {
+	return w.Stats().CurrentValue
+}
+
+// Created returns when the value was created or last restarted.
+// Exporters report it as start of cumulative series.
+func (w *ValueWrapper) Created() time.Time {
+	return time.Unix(0, w.created.Load())
+}
+
+// updateObservers fans out simv update hooks to registered observers,
+// subscriber channels and cursors.
+type updateObservers struct {
+	mu          sync.RWMutex
+	observers   []func(float64)
+	subscribers []chan float64
+	cursors     []*Cursor
+	closed      bool
+
+	input float64 // Input of the last transform of the current update
+}
+
+func (o *updateObservers) add(observer func(float64)) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.observers = append(o.observers, observer)
+}
+
+func (o *updateObservers) addCursor(c *Cursor) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.cursors = append(o.cursors, c)
+}
+
+func (o *updateObservers) subscribe() <-chan float64 {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ch := make(chan float64)
+	if o.closed {
+		close(ch)
+		return ch
+	}
+	o.subscribers = append(o.subscribers, ch)
+	return ch
+}
+
+// close closes all subscriber channels.
+// Must be called after the last AfterUpdate.
+func (o *updateObservers) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.closed {
+		return
+	}
+	o.closed = true
+	for _, ch := range o.subscribers {
+		close(ch)
+	}
+}
+
+// OnInput implements value.UpdateHook.
+func (o *updateObservers) OnInput(input, state float64) {}
+
+// OnTransform implements value.UpdateHook.
+// Transforms run in order, the input of the last one remains.
+func (o *updateObservers) OnTransform(name string, input, output, state float64) {
+	o.input = input
+}
+
+// AfterUpdate implements value.UpdateHook.
+// Called once per source value, which is processed when it returns.
+func (o *updateObservers) AfterUpdate(finalState float64) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	for _, observer := range o.observers {
+		observer(finalState)
+	}
+	for _, c := range o.cursors {
+		c.update(o.input, finalState)
+	}
+	activity.add(len(o.subscribers))
+	for _, ch := range o.subscribers {
+		ch <- finalState
+	}
+	activity.done()
 }
 
 // CreateValue creates a value from configuration.
 // The value is started and ready to receive updates.
+// resetClock drives on_interval and on_tick resets or restarts, it is nil otherwise.
 func CreateValue(
 	cfg config.ValueConfig,
-	src source.Publisher[int],
+	src source.Publisher[float64],
+	resetClock clock.Clock,
 ) (*ValueWrapper, error) {
 	if src == nil {
 		return nil, fmt.Errorf("source required for value")
@@ -29,7 +140,7 @@ func CreateValue(
 
 	// Add transforms
 	if len(cfg.Transforms) > 0 {
-		transforms, err := buildTransforms(cfg.Transforms)
+		transforms, err := buildTransforms(cfg.Transforms, cfg.Interval())
 		if err != nil {
 			return nil, err
 		}
@@ -38,25 +149,107 @@ func CreateValue(
 		}
 	}
 
-	// Apply reset behavior
-	if cfg.Reset.Type == "on_read" {
-		val.EnableResetOnRead(cfg.Reset.Value)
+	// Apply reset behavior, cursors of exporters reset on read
+	var reads *readReset
+	switch {
+	case cfg.Reset.Type == config.ResetOnRead:
+		reads = &readReset{
+			value:        cfg.Reset.Value,
+			accumulating: len(cfg.Transforms) > 0 && cfg.Transforms[len(cfg.Transforms)-1].Type == "accumulate",
+		}
+	case cfg.Reset.Type == config.ResetAtValue:
+		// Wraps the final state, after all configured transforms
+		val.AddTransform(&wrap{threshold: cfg.Reset.Threshold, reset: cfg.Reset.Value})
+	}
+
+	// The clock owns clock driven resets and restarts, exporters read without consuming
+	if cfg.ResetByClock() {
+		if resetClock == nil {
+			return nil, fmt.Errorf("reset or restart requires a clock")
+		}
+		resetValue := cfg.Reset.Value
+		if cfg.Restart != nil {
+			resetValue = cfg.Restart.Value
+		}
+		val.EnableResetOnRead(resetValue)
+	}
+
+	// Forward updates to observers
+	updates := &updateObservers{}
+	val.SetUpdateHook(updates)
+
+	w := &ValueWrapper{Value: val, updates: updates, reads: reads}
+	w.created.Store(Now().UnixNano())
+
+	if cfg.ResetByClock() {
+		ticks := resetClock.Subscribe()
+		restart := cfg.Restart != nil
+		go func() {
+			for range ticks {
+				val.Value()
+				if restart {
+					w.created.Store(Now().UnixNano())
+				}
+				activity.done()
+			}
+		}()
 	}
 
 	// Start the value (begins receiving updates)
 	val.Start()
 
-	return &ValueWrapper{Value: val}, nil
+	// Stop returns once the source closed and the last update is processed
+	go func() {
+		val.Stop()
+		updates.close()
+	}()
+
+	return w, nil
 }
 
 // buildTransforms creates transform instances from configuration.
-func buildTransforms(transformCfgs []config.TransformConfig) ([]transform.Transformation[int], error) {
-	var transforms []transform.Transformation[int]
+// The clock interval normalizes rate transforms.
+func buildTransforms(transformCfgs []config.TransformConfig, interval time.Duration) ([]transform.Transformation[float64], error) {
+	var transforms []transform.Transformation[float64]
 
-	for _, tfCfg := range transformCfgs {
+	for i, tfCfg := range transformCfgs {
 		switch tfCfg.Type {
 		case "accumulate":
-			transforms = append(transforms, transform.NewAccumulate[int]())
+			transforms = append(transforms, newAccumulate(i == len(transformCfgs)-1))
+		case "delta":
+			transforms = append(transforms, &delta{})
+		case "rate":
+			if interval <= 0 {
+				return nil, fmt.Errorf("rate transform requires a clock interval")
+			}
+			transforms = append(transforms, newRate(interval))
+		case "scale":
+			if tfCfg.Factor == nil {
+				return nil, fmt.Errorf("scale transform requires a factor")
+			}
+			transforms = append(transforms, &scale{factor: *tfCfg.Factor})
+		case "offset":
+			if tfCfg.Offset == nil {
+				return nil, fmt.Errorf("offset transform requires an offset")
+			}
+			transforms = append(transforms, &offset{offset: *tfCfg.Offset})
+		case "clamp":
+			transforms = append(transforms, newClamp(tfCfg.Min, tfCfg.Max))
+		case "quantize":
+			if tfCfg.Step == nil || *tfCfg.Step <= 0 {
+				return nil, fmt.Errorf("quantize transform requires a step > 0")
+			}
+			transforms = append(transforms, &quantize{step: *tfCfg.Step})
+		case "moving_average":
+			if tfCfg.Window == nil || *tfCfg.Window < 1 {
+				return nil, fmt.Errorf("moving_average transform requires a window >= 1")
+			}
+			transforms = append(transforms, newMovingAverage(*tfCfg.Window))
+		case "ewma":
+			if tfCfg.Alpha == nil || *tfCfg.Alpha <= 0 || *tfCfg.Alpha > 1 {
+				return nil, fmt.Errorf("ewma transform requires an alpha in (0, 1]")
+			}
+			transforms = append(transforms, &ewma{alpha: *tfCfg.Alpha})
 		case "":
 			return nil, fmt.Errorf("transform type cannot be empty")
 		default: